@@ -0,0 +1,284 @@
+package excel
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/xuri/excelize/v2"
+)
+
+// CaptionKind is the kind of a caption.
+type CaptionKind int
+
+const (
+	CaptionFigure CaptionKind = iota // 図
+	CaptionTable                     // 表
+)
+
+// String returns the label of the caption kind, such as "図".
+func (k CaptionKind) String() string {
+	switch k {
+	case CaptionFigure:
+		return "図"
+	case CaptionTable:
+		return "表"
+	}
+	return "CaptionKind(" + strconv.Itoa(int(k)) + ")"
+}
+
+// 相互参照 (例: {{ref:tbl-policies}})
+var captionRef = regexp.MustCompile(`\{\{ref:([^{}\s]+)\}\}`)
+
+// caption is a caption written by Caption.
+type caption struct {
+	kind   CaptionKind
+	id     string // 相互参照の ID (空の場合は参照できない)
+	sheet  string
+	cell   string
+	text   string // 番号を含まないキャプション
+	number string // SaveAndClose で決まる番号 (例: 図 1-4)
+}
+
+// Caption writes a caption of a figure or a table at the cursor, and moves
+// the cursor to the next row. The captions are numbered per H1 chapter,
+// such as "表 3-2", by SaveAndClose; the captions before the first H1 are
+// numbered such as "表 2".
+//
+// The optional id names the caption for the cross-references. The text
+// written by SetVal can refer to it as {{ref:id}}, which SaveAndClose
+// replaces with the number of the caption, such as "表 3-2", and links
+// the cell to the caption. An unknown id is an error of SaveAndClose. The
+// references are kept as they are if Caption is never called, and
+// ExecuteTemplate keeps them too.
+//
+// Example:
+//
+//	err := e.Caption(CaptionTable, "ポリシー一覧", "tbl-policies")
+//	...
+//	err = e.LF().SetVal("{{ref:tbl-policies}} に従って設定する。")
+func (e *Excel) Caption(kind CaptionKind, text string, id ...string) error {
+	if kind != CaptionFigure && kind != CaptionTable {
+		return fmt.Errorf("Caption: unknown kind %d", kind)
+	}
+	c := caption{kind: kind, sheet: e.sheet, text: text}
+	if len(id) > 0 && id[0] != "" {
+		if !captionRef.MatchString("{{ref:" + id[0] + "}}") {
+			return fmt.Errorf("Caption: invalid id '%s'", id[0])
+		}
+		if slices.ContainsFunc(e.captions, func(c caption) bool {
+			return c.id == id[0]
+		}) {
+			return fmt.Errorf("Caption: duplicate id '%s'", id[0])
+		}
+		c.id = id[0]
+	}
+	cell, err := e.Cell()
+	if err != nil {
+		return fmt.Errorf("Caption: %w", err)
+	}
+	c.cell = cell
+	// 番号は SaveAndClose で付ける
+	if err := e.SetVal(kind.String() + " " + text); err != nil {
+		return fmt.Errorf("Caption: %w", err)
+	}
+	e.captions = append(e.captions, c)
+	e.Row++
+	return nil
+}
+
+// resolveCaptions numbers the captions per H1 chapter, and replaces the
+// cross-references in every sheet with the numbers. Nothing is done if
+// Caption has not been called, so that a text like {{ref:id}} in an opened
+// workbook is kept.
+func (e *Excel) resolveCaptions() error {
+	if len(e.captions) == 0 {
+		return nil
+	}
+	if err := e.numberCaptions(); err != nil {
+		return err
+	}
+	ids := make(map[string]caption)
+	for _, c := range e.captions {
+		if c.id != "" {
+			ids[c.id] = c
+		}
+	}
+	sheets := e.f.GetSheetList()
+	for i, sheet := range sheets {
+		e.reportProgress(i+1, len(sheets), "Caption: "+sheet)
+		rows, err := e.f.GetRows(sheet, excelize.Options{RawCellValue: true})
+		if err != nil {
+			return err
+		}
+		for r, row := range rows {
+			for c, value := range row {
+				if !strings.Contains(value, "{{ref:") {
+					continue
+				}
+				cell, err := excelize.CoordinatesToCellName(c+1, r+1)
+				if err != nil {
+					return err
+				}
+				if err := e.resolveRefs(sheet, cell, value, ids); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// numberCaptions numbers the captions in the order of the sheets, rows
+// and columns, counting the H1 headers before them as the chapter.
+func (e *Excel) numberCaptions() error {
+	var (
+		chapter  int
+		count    = make(map[CaptionKind]int) // 章ごとの番号
+		captions []caption                   // 文書の順に並べたキャプション
+	)
+	for _, sheet := range e.f.GetSheetList() {
+		marks, err := e.GetHeaderMarks(sheet)
+		if err != nil {
+			return err
+		}
+		// シート内のヘッダとキャプションを行と列の順に並べる
+		type entry struct {
+			col, row int
+			caption  int // e.captions の添字 (ヘッダの場合は -1)
+		}
+		var entries []entry
+		for _, mark := range marks {
+			if mark.Level != 1 {
+				continue
+			}
+			col, row, err := excelize.CellNameToCoordinates(mark.Cell)
+			if err != nil {
+				return err
+			}
+			entries = append(entries, entry{col, row, -1})
+		}
+		for i, c := range e.captions {
+			if c.sheet != sheet {
+				continue
+			}
+			col, row, err := excelize.CellNameToCoordinates(c.cell)
+			if err != nil {
+				return err
+			}
+			entries = append(entries, entry{col, row, i})
+		}
+		slices.SortStableFunc(entries, func(a, b entry) int {
+			if a.row != b.row {
+				return a.row - b.row
+			}
+			return a.col - b.col
+		})
+		for _, en := range entries {
+			if en.caption == -1 {
+				chapter++
+				count = make(map[CaptionKind]int)
+				continue
+			}
+			c := e.captions[en.caption]
+			count[c.kind]++
+			if chapter == 0 {
+				c.number = fmt.Sprintf("%s %d", c.kind, count[c.kind])
+			} else {
+				c.number = fmt.Sprintf("%s %d-%d", c.kind, chapter,
+					count[c.kind])
+			}
+			if err := e.f.SetCellStr(c.sheet, c.cell,
+				c.number+" "+c.text); err != nil {
+				return err
+			}
+			captions = append(captions, c)
+		}
+	}
+	// 削除されたシートのキャプションは除く
+	e.captions = captions
+	return nil
+}
+
+// resolveRefs replaces the cross-references in the value of the cell with
+// the numbers of the captions, and links the cell to the first caption.
+func (e *Excel) resolveRefs(sheet, cell, value string,
+	ids map[string]caption) error {
+	var (
+		target  *caption
+		unknown string
+	)
+	value = captionRef.ReplaceAllStringFunc(value, func(ref string) string {
+		id := captionRef.FindStringSubmatch(ref)[1]
+		c, ok := ids[id]
+		if !ok {
+			if unknown == "" {
+				unknown = id
+			}
+			return ref
+		}
+		if target == nil {
+			target = &c
+		}
+		return c.number
+	})
+	if unknown != "" {
+		return fmt.Errorf("unknown reference '%s' at cell '%s' in sheet '%s'",
+			unknown, cell, sheet)
+	}
+	if target == nil {
+		return nil
+	}
+	if err := e.f.SetCellStr(sheet, cell, value); err != nil {
+		return err
+	}
+	cellAbs, err := RelCellNameToAbsCellName(target.cell)
+	if err != nil {
+		return err
+	}
+	return e.f.SetCellHyperLink(sheet, cell,
+		fmt.Sprintf("'%s'!%s", target.sheet, cellAbs), "Location")
+}
+
+// makeListOfCaptions writes the lists of the figures and the tables with
+// hyperlinks at the cursor.
+func (e *Excel) makeListOfCaptions() error {
+	for _, kind := range []CaptionKind{CaptionFigure, CaptionTable} {
+		first := true
+		for _, c := range e.captions {
+			if c.kind != kind {
+				continue
+			}
+			if first {
+				if err := e.CR(2).LF(2).SetVal(kind.String() + "目次"); err != nil {
+					return err
+				}
+				if err := e.SetStyle(NewStyle(fontBold)); err != nil {
+					return err
+				}
+				first = false
+			}
+			if err := e.CR(3).LF().SetVal(c.number + " " + c.text); err != nil {
+				return err
+			}
+			cell, err := e.Cell()
+			if err != nil {
+				return err
+			}
+			cellAbs, err := RelCellNameToAbsCellName(c.cell)
+			if err != nil {
+				return err
+			}
+			if err := e.f.SetCellHyperLink(e.sheet, cell,
+				fmt.Sprintf("'%s'!%s", c.sheet, cellAbs), "Location"); err != nil {
+				return err
+			}
+			if err := e.SetStyle(NewStyle(fontHyperLink)); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
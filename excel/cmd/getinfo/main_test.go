@@ -0,0 +1,101 @@
+package main
+
+import (
+	"bytes"
+	"flag"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/nonsugar-go/tools/excel"
+	"github.com/nonsugar-go/tools/excel/internal/inspect"
+)
+
+var update = flag.Bool("update", false, "update golden files in testdata")
+
+// fixtures are the workbooks of the regression tests.
+//
+//   - Sample1.xlsx: Excel で保存したブック
+//   - minimal.xlsx: 必須の要素だけのブック (styles.xml, sheetPr, pageSetup などがない)
+//   - comments.xlsx: リッチテキストのないコメントや空のコメントを含むブック
+//   - broken.xlsx: 不正なヘッダの印や作成者のないコメントを含むブック
+var fixtures = []string{
+	"../../testdata/Sample1.xlsx",
+	"testdata/minimal.xlsx",
+	"testdata/comments.xlsx",
+	"testdata/broken.xlsx",
+}
+
+func TestGetinfo(t *testing.T) {
+	opts := inspect.Options{
+		AllSheets: true,
+		ColMin:    1,
+		ColMax:    8,
+		RowMin:    1,
+		RowMax:    8,
+		RangeRef:  "A1:C3",
+	}
+	for _, filename := range fixtures {
+		name := strings.TrimSuffix(filepath.Base(filename), ".xlsx")
+		t.Run(name, func(t *testing.T) {
+			e, err := excel.OpenExcel(filename)
+			if err != nil {
+				t.Fatalf("OpenExcel: want no error, but %v", err)
+			}
+			defer e.Close()
+			info, err := inspect.Collect(e, filepath.ToSlash(filename), opts)
+			if err != nil {
+				t.Fatalf("Collect: want no error, but %v", err)
+			}
+			for _, format := range []string{formatText, formatYAML} {
+				if err := write(io.Discard, info, format); err != nil {
+					t.Errorf("write %s: want no error, but %v", format, err)
+				}
+			}
+
+			var got bytes.Buffer
+			if err := write(&got, info, formatJSON); err != nil {
+				t.Fatalf("write json: want no error, but %v", err)
+			}
+			golden := filepath.Join("testdata", name+".golden.json")
+			if *update {
+				if err := os.WriteFile(golden, got.Bytes(), 0644); err != nil {
+					t.Fatalf("WriteFile: want no error, but %v", err)
+				}
+			}
+			want, err := os.ReadFile(golden)
+			if err != nil {
+				t.Fatalf("ReadFile: want no error, but %v", err)
+			}
+			if !bytes.Equal(got.Bytes(), want) {
+				t.Errorf("json output differs from %s; run go test -update",
+					golden)
+			}
+		})
+	}
+}
+
+func TestVal(t *testing.T) {
+	var nilBool *bool
+	b, n, s := true, uint(100), "portrait"
+	tests := []struct {
+		input any
+		want  string
+	}{
+		{nil, unset},
+		{nilBool, unset},
+		{&b, "true"},
+		{&n, "100"},
+		{&s, `"portrait"`},
+	}
+	for _, tt := range tests {
+		if got := val(tt.input); got != tt.want {
+			t.Errorf("val(%v): want %s, but %s", tt.input, tt.want, got)
+		}
+	}
+	if err := write(io.Discard, &inspect.Info{}, "xml"); err == nil {
+		t.Errorf("write xml: want error, but %v", err)
+	}
+}
@@ -0,0 +1,296 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/nonsugar-go/tools/excel/internal/inspect"
+	"gopkg.in/yaml.v3"
+)
+
+// Output formats.
+const (
+	formatText = "text"
+	formatJSON = "json"
+	formatYAML = "yaml"
+)
+
+// write writes the information in the format.
+func write(w io.Writer, info *inspect.Info, format string) error {
+	switch format {
+	case formatText:
+		return writeText(w, info)
+	case formatJSON:
+		return writeJSON(w, info)
+	case formatYAML:
+		return writeYAML(w, info)
+	}
+	return fmt.Errorf("unknown format %q: want %s, %s or %s",
+		format, formatText, formatJSON, formatYAML)
+}
+
+// writeJSON writes the information as indented JSON.
+func writeJSON(w io.Writer, info *inspect.Info) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	enc.SetEscapeHTML(false)
+	return enc.Encode(info)
+}
+
+// writeYAML writes the information as YAML.
+func writeYAML(w io.Writer, info *inspect.Info) error {
+	enc := yaml.NewEncoder(w)
+	enc.SetIndent(2)
+	if err := enc.Encode(info); err != nil {
+		return err
+	}
+	return enc.Close()
+}
+
+// unset is the text of an optional property which is not set.
+const unset = "unset"
+
+// val formats a value of the text output. A pointer is dereferenced, and
+// a nil pointer is formatted as unset.
+func val(v any) string {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() == reflect.Pointer {
+		if rv.IsNil() {
+			return unset
+		}
+		rv = rv.Elem()
+	}
+	if !rv.IsValid() {
+		return unset
+	}
+	switch rv.Kind() {
+	case reflect.String, reflect.Struct:
+		return fmt.Sprintf("%#v", rv.Interface())
+	}
+	return fmt.Sprintf("%v", rv.Interface())
+}
+
+// writeWarnings writes the warnings as text, if any.
+func writeWarnings(w *tabwriter.Writer, title, line string, warnings []string) {
+	if len(warnings) == 0 {
+		return
+	}
+	fmt.Fprintln(w, line)
+	fmt.Fprintln(w, title)
+	for _, warning := range warnings {
+		fmt.Fprintln(w, warning)
+	}
+	w.Flush()
+}
+
+// writeText writes the information as tab-aligned text.
+func writeText(out io.Writer, info *inspect.Info) error {
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	line := strings.Repeat("-", 72)
+
+	fmt.Fprintln(w, line)
+	fmt.Fprintln(w, "[WORKBOOK PROPS]")
+	fmt.Fprint(w, "KEY\tVALUE\t\n")
+	fmt.Fprintf(w, "Default Font\t%s\t\n", val(info.Workbook.DefaultFont))
+	fmt.Fprintf(w, "CodeName\t%s\t\n", val(info.Workbook.CodeName))
+	fmt.Fprintf(w, "Date1904\t%s\t\n", val(info.Workbook.Date1904))
+	fmt.Fprintf(w, "FilterPrivacy\t%s\t\n", val(info.Workbook.FilterPrivacy))
+	w.Flush()
+
+	fmt.Fprintln(w, line)
+	fmt.Fprintln(w, "[DEFINED NAME]")
+	fmt.Fprint(w, "IDX\tNAME\tComment\tRefersTo\tScope\t\n")
+	for i, dn := range info.DefinedNames {
+		fmt.Fprintf(w, "%d\t%#v\t%#v\t%#v\t%#v\t\n",
+			i, dn.Name, dn.Comment, dn.RefersTo, dn.Scope)
+	}
+	w.Flush()
+
+	fmt.Fprintln(w, line)
+	fmt.Fprintln(w, "[SHEET LIST]")
+	fmt.Fprint(w, "IDX\tSHEET NAME\t\n")
+	for _, s := range info.SheetList {
+		fmt.Fprintf(w, "%d\t%s\t\n", s.Index, s.Name)
+	}
+	w.Flush()
+
+	fmt.Fprintln(w, line)
+	fmt.Fprintln(w, "[SUMMARY]")
+	fmt.Fprint(w,
+		"IDX\tSHEET NAME\tUSED RANGE\tMERGED\tCOMMENTS\tHEADERS\tTABLES\tNAMES\t\n")
+	for _, d := range info.Sheets {
+		s := d.Summary
+		fmt.Fprintf(w, "%d\t%s\t%s\t%d\t%d\t%d\t%d\t%d\t\n",
+			d.Index, d.Name, s.UsedRange, s.MergedCells, s.Comments,
+			len(s.HeaderMarks), len(s.Tables), len(s.DefinedNames))
+	}
+	w.Flush()
+
+	for _, d := range info.Sheets {
+		writeSheetText(w, &d, line)
+	}
+	writeWarnings(w, "[WARNINGS]", line, info.Warnings)
+	return w.Flush()
+}
+
+// writeSheetText writes the detailed information of a sheet as text.
+func writeSheetText(w *tabwriter.Writer, d *inspect.SheetDetails, line string) {
+	sheet := d.Name
+
+	fmt.Fprintln(w, line)
+	fmt.Fprintf(w, "[HEADER MARKS:%s]\n", sheet)
+	fmt.Fprint(w, "CELL\tLEVEL\tVALUE\t\n")
+	for _, m := range d.Summary.HeaderMarks {
+		fmt.Fprintf(w, "%s\t%d\t%s\t\n", m.Cell, m.Level, m.Value)
+	}
+	w.Flush()
+
+	fmt.Fprintln(w, line)
+	fmt.Fprintf(w, "[TABLES:%s]\n", sheet)
+	fmt.Fprint(w, "NAME\tRANGE\tSTYLE\t\n")
+	for _, t := range d.Summary.Tables {
+		fmt.Fprintf(w, "%s\t%s\t%s\t\n", t.Name, t.Range, t.StyleName)
+	}
+	w.Flush()
+
+	fmt.Fprintln(w, line)
+	fmt.Fprintf(w, "[SHEET PROPS:%s]\n", sheet)
+	fmt.Fprint(w, "KEY\tVALUE\t\n")
+	p := d.Props
+	fmt.Fprintf(w, "AutoPageBreaks\t%s\t\n", val(p.AutoPageBreaks))
+	fmt.Fprintf(w, "BaseColWidth\t%s\t\n", val(p.BaseColWidth))
+	fmt.Fprintf(w, "CodeName\t%s\t\n", val(p.CodeName))
+	fmt.Fprintf(w, "CustomHeight\t%s\t\n", val(p.CustomHeight))
+	fmt.Fprintf(w, "DefaultColWidth\t%s\t\n", val(p.DefaultColWidth))
+	fmt.Fprintf(w, "DefaultRowHeight\t%s\t\n", val(p.DefaultRowHeight))
+	fmt.Fprintf(w, "EnableFormatConditionsCalculation\t%s\t\n",
+		val(p.EnableFormatConditionsCalculation))
+	fmt.Fprintf(w, "FitToPage\t%s\t\n", val(p.FitToPage))
+	fmt.Fprintf(w, "OutlineSummaryBelow\t%s\t\n", val(p.OutlineSummaryBelow))
+	fmt.Fprintf(w, "OutlineSummaryRight\t%s\t\n", val(p.OutlineSummaryRight))
+	fmt.Fprintf(w, "Published\t%s\t\n", val(p.Published))
+	fmt.Fprintf(w, "TabColorIndexed\t%s\t\n", val(p.TabColorIndexed))
+	fmt.Fprintf(w, "TabColorRGB\t%s\t\n", val(p.TabColorRGB))
+	fmt.Fprintf(w, "TabColorTheme\t%s\t\n", val(p.TabColorTheme))
+	fmt.Fprintf(w, "TabColorTint\t%s\t\n", val(p.TabColorTint))
+	fmt.Fprintf(w, "ThickBottom\t%s\t\n", val(p.ThickBottom))
+	fmt.Fprintf(w, "ThickTop\t%s\t\n", val(p.ThickTop))
+	fmt.Fprintf(w, "ZeroHeight\t%s\t\n", val(p.ZeroHeight))
+	w.Flush()
+
+	fmt.Fprintln(w, line)
+	fmt.Fprintf(w, "[PAGE LAYOUT:%s]\n", sheet)
+	l := d.PageLayout
+	fmt.Fprintf(w, "AdjustTo\t%s\t\n", val(l.AdjustTo))
+	fmt.Fprintf(w, "BlackAndWhite\t%s\t\n", val(l.BlackAndWhite))
+	fmt.Fprintf(w, "FirstPageNumber\t%s\t\n", val(l.FirstPageNumber))
+	fmt.Fprintf(w, "FitToHeight\t%s\t\n", val(l.FitToHeight))
+	fmt.Fprintf(w, "FitToWidth\t%s\t\n", val(l.FitToWidth))
+	fmt.Fprintf(w, "Orientation\t%s\t\n", val(l.Orientation))
+	fmt.Fprintf(w, "PageOrder\t%s\t\n", val(l.PageOrder))
+	fmt.Fprintf(w, "Size\t%s\t\n", val(l.Size))
+	w.Flush()
+
+	fmt.Fprintln(w, line)
+	fmt.Fprintf(w, "[PAGE MARGINS:%s]\n", sheet)
+	m := d.PageMargins
+	fmt.Fprintf(w, "Bottom\t%s\t\n", val(m.Bottom))
+	fmt.Fprintf(w, "Footer\t%s\t\n", val(m.Footer))
+	fmt.Fprintf(w, "Header\t%s\t\n", val(m.Header))
+	fmt.Fprintf(w, "Horizontally\t%s\t\n", val(m.Horizontally))
+	fmt.Fprintf(w, "Left\t%s\t\n", val(m.Left))
+	fmt.Fprintf(w, "Right\t%s\t\n", val(m.Right))
+	fmt.Fprintf(w, "Top\t%s\t\n", val(m.Top))
+	fmt.Fprintf(w, "Vertically\t%s\t\n", val(m.Vertically))
+	w.Flush()
+
+	fmt.Fprintln(w, line)
+	fmt.Fprintf(w, "[HEADER FOOTER:%s]\n", sheet)
+	hf := d.HeaderFooter
+	fmt.Fprintf(w, "AlignWithMargins\t%s\t\n", val(hf.AlignWithMargins))
+	fmt.Fprintf(w, "DifferentFirst\t%#v\t\n", hf.DifferentFirst)
+	fmt.Fprintf(w, "DifferentOddEven\t%#v\t\n", hf.DifferentOddEven)
+	fmt.Fprintf(w, "EvenFooter\t%#v\t\n", hf.EvenFooter)
+	fmt.Fprintf(w, "EvenHeader\t%#v\t\n", hf.EvenHeader)
+	fmt.Fprintf(w, "FirstFooter\t%#v\t\n", hf.FirstFooter)
+	fmt.Fprintf(w, "FirstHeader\t%#v\t\n", hf.FirstHeader)
+	fmt.Fprintf(w, "OddFooter\t%#v\t\n", hf.OddFooter)
+	fmt.Fprintf(w, "OddHeader\t%#v\t\n", hf.OddHeader)
+	fmt.Fprintf(w, "ScaleWithDoc\t%s\t\n", val(hf.ScaleWithDoc))
+	w.Flush()
+
+	fmt.Fprintln(w, line)
+	fmt.Fprintf(w, "[COLUMNS:%s]\n", sheet)
+	fmt.Fprint(w, "COL\tVISIBLE\tWIDTH\t\n")
+	for _, c := range d.Columns {
+		fmt.Fprintf(w, "%s\t%#v\t%#v\t\n", c.Name, c.Visible, c.Width)
+	}
+	w.Flush()
+
+	fmt.Fprintln(w, line)
+	fmt.Fprintf(w, "[ROWS:%s]\n", sheet)
+	fmt.Fprint(w, "ROW\tVISIBLE\tHEIGHT\t\n")
+	for _, r := range d.Rows {
+		fmt.Fprintf(w, "%d\t%#v\t%#v\t\n", r.Number, r.Visible, r.Height)
+	}
+	w.Flush()
+
+	fmt.Fprintln(w, line)
+	fmt.Fprintf(w, "[COMMENTS:%s]\n", sheet)
+	fmt.Fprint(w,
+		"IDX\tAUTHOR\tAUTHOR ID\tCELL\tTEXT\tWIDTH\tHEIGHT\tPARAGRAPH FONT\tPARAGRAPH TEXT\t\n")
+	for i, c := range d.Comments {
+		font, text := unset, ""
+		if len(c.Paragraph) > 0 {
+			font, text = val(c.Paragraph[0].Font), c.Paragraph[0].Text
+		}
+		fmt.Fprintf(w, "%d\t%#v\t%d\t%#v\t%#v\t%d\t%d\t%s\t%#v\t\n",
+			i, c.Author, c.AuthorID, c.Cell, c.Text, c.Width, c.Height,
+			font, text)
+	}
+	w.Flush()
+
+	fmt.Fprintln(w, line)
+	fmt.Fprintf(w, "[CELLS:%s]\n", sheet)
+	fmt.Fprint(w, "CELL\tVALUE\tTYPE\tMERGED\tHYPER LINK\tSTYLE\tFLAGS\t\n")
+	var styleIDs []int // 出現順のスタイル ID
+	styles := map[int]*inspect.Style{}
+	for _, c := range d.Cells {
+		flags := ""
+		if c.Style != nil {
+			flags = strings.Join(c.Style.Flags, "|")
+			if !c.Style.FlagsExact {
+				flags += " (partial)"
+			}
+		}
+		fmt.Fprintf(w, "%s\t%#v\t%s\t%s\t%s\t%d\t%s\t\n",
+			c.Cell, c.Value, c.Type, c.Merged, c.HyperLink, c.StyleID, flags)
+		if _, ok := styles[c.StyleID]; !ok {
+			styleIDs = append(styleIDs, c.StyleID)
+			styles[c.StyleID] = c.Style
+		}
+	}
+	w.Flush()
+
+	for _, id := range styleIDs {
+		fmt.Fprintln(w, line)
+		fmt.Fprintf(w, "[CELL STYLE(%d):%s]\n", id, sheet)
+		if s := styles[id]; s != nil {
+			fmt.Fprintf(w, "FLAGS\t%s\t\n", strings.Join(s.Flags, "|"))
+			fmt.Fprintf(w, "FLAGS EXACT\t%#v\t\n", s.FlagsExact)
+			fmt.Fprintf(w, "BORDER\t%#v\t\n", s.Border)
+			fmt.Fprintf(w, "FILL\t%#v\t\n", s.Fill)
+			fmt.Fprintf(w, "FONT\t%s\t\n", val(s.Font))
+			fmt.Fprintf(w, "ALIGNMENT\t%s\t\n", val(s.Alignment))
+			fmt.Fprintf(w, "NUMFMT\t%d\t\n", s.NumFmt)
+			fmt.Fprintf(w, "CUSTOM NUMFMT\t%s\t\n", val(s.CustomNumFmt))
+		}
+		w.Flush()
+	}
+
+	writeWarnings(w, fmt.Sprintf("[WARNINGS:%s]", sheet), line, d.Warnings)
+}
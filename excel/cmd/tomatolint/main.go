@@ -0,0 +1,105 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+
+	"github.com/nonsugar-go/tools/excel"
+)
+
+const logFilename = "tomatolint.log"
+
+// 終了コード
+const (
+	exitOK        = 0 // 違反なし
+	exitViolation = 1 // 違反あり
+	exitError     = 2 // エラー
+)
+
+func main() {
+	logFile, err := os.OpenFile(logFilename,
+		os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		panic(err)
+	}
+	defer logFile.Close()
+	slog.SetDefault(slog.New(slog.NewJSONHandler(logFile,
+		&slog.HandlerOptions{
+			AddSource: true,
+		})))
+
+	var (
+		filename string
+		fix      bool
+		fontSize float64
+	)
+	flag.StringVar(&filename, "in", "", "Excel ファイル (*.xlsx)")
+	flag.BoolVar(&fix, "fix", false,
+		"fix the page setup and the default font, and save the workbook")
+	flag.Float64Var(&fontSize, "font-size", 10, "default font size")
+	flag.Parse()
+	if filename == "" {
+		slog.Error("Excel ファイルが指定されていません")
+		fmt.Println("Excel ファイルが指定されていません")
+		os.Exit(exitError)
+	}
+
+	n, err := run(os.Stdout, filename, fix, fontSize)
+	if err != nil {
+		slog.Error("検査できません", "error", err)
+		fmt.Printf("検査できません: %v\n", err)
+		os.Exit(exitError)
+	}
+	if n > 0 {
+		os.Exit(exitViolation)
+	}
+	os.Exit(exitOK)
+}
+
+// run checks the workbook, and fixes it if fix is true. It returns the
+// number of the remaining violations.
+func run(w io.Writer, filename string, fix bool,
+	fontSize float64) (int, error) {
+	e, err := excel.OpenExcel(filename)
+	if err != nil {
+		return 0, err
+	}
+	defer func() {
+		if err := e.Close(); err != nil {
+			slog.Error("Excel ファイルが閉じられません", "error", err)
+		}
+	}()
+	violations, err := e.Lint(fontSize)
+	if err != nil {
+		return 0, err
+	}
+	if fix {
+		fixed, err := e.Fix(violations)
+		if err != nil {
+			return 0, err
+		}
+		if fixed > 0 {
+			// SaveAndClose は目次を作り直すので使わない
+			if err := e.GetFile().Save(); err != nil {
+				return 0, fmt.Errorf("cannot save excel book: %s: %w",
+					filename, err)
+			}
+			slog.Info("修正しました", "file", filename, "fixed", fixed)
+		}
+		fmt.Fprintf(w, "%s: fixed %d violations\n", filename, fixed)
+		if violations, err = e.Lint(fontSize); err != nil {
+			return 0, err
+		}
+	}
+	for _, v := range violations {
+		if v.Fixable() {
+			fmt.Fprintf(w, "%s (fixable)\n", v)
+		} else {
+			fmt.Fprintln(w, v)
+		}
+	}
+	return len(violations), nil
+}
@@ -0,0 +1,64 @@
+package main
+
+import (
+	"bytes"
+	"io"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/nonsugar-go/tools/excel"
+	"github.com/xuri/excelize/v2"
+)
+
+func TestRun(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "lint.xlsx")
+	e, err := excel.New(filename)
+	if err != nil {
+		t.Fatalf("New: want no error, but %v", err)
+	}
+	if err := e.NewSheet("本文", excel.SheetTypeNormal); err != nil {
+		t.Fatalf("NewSheet: want no error, but %v", err)
+	}
+	orientation := "landscape"
+	if err := e.GetFile().SetPageLayout("本文", &excelize.PageLayoutOptions{
+		Orientation: &orientation}); err != nil {
+		t.Fatalf("SetPageLayout: want no error, but %v", err)
+	}
+	if err := e.SaveAndClose(); err != nil {
+		t.Fatalf("SaveAndClose: want no error, but %v", err)
+	}
+
+	tests := []struct {
+		name string
+		fix  bool
+		want int
+		out  string
+	}{
+		{"lint", false, 1, "本文: orientation: orientation landscape, want portrait (fixable)"},
+		{"fix", true, 0, "fixed 1 violations"},
+		{"lint after fix", false, 0, ""},
+	}
+	for _, tt := range tests {
+		var out bytes.Buffer
+		got, err := run(&out, filename, tt.fix, 10)
+		if err != nil {
+			t.Fatalf("%s: want no error, but %v", tt.name, err)
+		}
+		if got != tt.want {
+			t.Errorf("%s: want %d violations, but %d: %s",
+				tt.name, tt.want, got, out.String())
+		}
+		if !strings.Contains(out.String(), tt.out) {
+			t.Errorf("%s: want %q, but %q", tt.name, tt.out, out.String())
+		}
+	}
+
+	if _, err := run(io.Discard, "../../testdata/Sample1.xlsx", false,
+		10); err != nil {
+		t.Errorf("Sample1: want no error, but %v", err)
+	}
+	if _, err := run(io.Discard, "not-found.xlsx", false, 10); err == nil {
+		t.Errorf("not-found: want error, but %v", err)
+	}
+}
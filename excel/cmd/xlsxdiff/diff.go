@@ -0,0 +1,371 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+
+	"github.com/nonsugar-go/tools/excel/internal/inspect"
+	"github.com/xuri/excelize/v2"
+)
+
+// op is the operation of a change.
+type op string
+
+const (
+	opAdded   op = "added"
+	opRemoved op = "removed"
+	opRenamed op = "renamed"
+	opChanged op = "changed"
+)
+
+// kind is the kind of a change.
+type kind string
+
+const (
+	kindSheet     kind = "sheet"
+	kindValue     kind = "value"
+	kindStyle     kind = "style"
+	kindMerge     kind = "merge"
+	kindComment   kind = "comment"
+	kindHeader    kind = "header"
+	kindPageSetup kind = "page-setup"
+)
+
+// renameThreshold is the minimum similarity of the cell values for an
+// unmatched pair of sheets to be reported as a rename.
+const renameThreshold = 0.5
+
+// change is a difference between the old and the new workbook.
+type change struct {
+	Op    op
+	Kind  kind
+	Sheet string // 新しいブックのシート名 (削除された場合は古いブックのシート名)
+	Item  string // セル番地、結合範囲、ページ設定の項目名など
+	Old   string
+	New   string
+}
+
+// diff compares the information of two workbooks. Sheets are matched by
+// name, and the remaining sheets are matched by the similarity of their
+// cell values to detect renames.
+func diff(oldInfo, newInfo *inspect.Info) []change {
+	var changes []change
+	oldSheets := make(map[string]*inspect.SheetDetails)
+	for i := range oldInfo.Sheets {
+		oldSheets[oldInfo.Sheets[i].Name] = &oldInfo.Sheets[i]
+	}
+	newSheets := make(map[string]*inspect.SheetDetails)
+	for i := range newInfo.Sheets {
+		newSheets[newInfo.Sheets[i].Name] = &newInfo.Sheets[i]
+	}
+
+	// 名前が一致しないシートは、セルの値が似ていれば名前の変更とみなす
+	renamed := make(map[string]*inspect.SheetDetails) // 新しい名前 -> 古いシート
+	paired := make(map[string]bool)                   // 古い名前
+	for i := range oldInfo.Sheets {
+		o := &oldInfo.Sheets[i]
+		if newSheets[o.Name] != nil {
+			continue
+		}
+		best, bestSim := "", renameThreshold
+		for j := range newInfo.Sheets {
+			n := &newInfo.Sheets[j]
+			if oldSheets[n.Name] != nil || renamed[n.Name] != nil {
+				continue
+			}
+			sim := similarity(o, n)
+			if sim > bestSim || best == "" && sim >= bestSim {
+				best, bestSim = n.Name, sim
+			}
+		}
+		if best != "" {
+			renamed[best] = o
+			paired[o.Name] = true
+		}
+	}
+
+	for i := range oldInfo.Sheets {
+		o := &oldInfo.Sheets[i]
+		if newSheets[o.Name] == nil && !paired[o.Name] {
+			changes = append(changes, change{
+				Op: opRemoved, Kind: kindSheet, Sheet: o.Name, Item: o.Name,
+				Old: o.Name,
+			})
+		}
+	}
+	for i := range newInfo.Sheets {
+		n := &newInfo.Sheets[i]
+		o := oldSheets[n.Name]
+		if o == nil {
+			if o = renamed[n.Name]; o == nil {
+				changes = append(changes, change{
+					Op: opAdded, Kind: kindSheet, Sheet: n.Name, Item: n.Name,
+					New: n.Name,
+				})
+				continue
+			}
+			changes = append(changes, change{
+				Op: opRenamed, Kind: kindSheet, Sheet: n.Name, Item: n.Name,
+				Old: o.Name, New: n.Name,
+			})
+		}
+		changes = append(changes, diffSheet(o, n)...)
+	}
+	return changes
+}
+
+// similarity returns the ratio of the cells which have the same value in
+// both sheets to the non-empty cells. Empty sheets have no similarity, so
+// that they are not reported as a rename.
+func similarity(o, n *inspect.SheetDetails) float64 {
+	ov, nv := cellValues(o), cellValues(n)
+	total := max(len(ov), len(nv))
+	if total == 0 {
+		return 0
+	}
+	same := 0
+	for cell, v := range ov {
+		if nv[cell] == v {
+			same++
+		}
+	}
+	return float64(same) / float64(total)
+}
+
+// cellValues returns the non-empty values of the sheet by cell.
+func cellValues(d *inspect.SheetDetails) map[string]string {
+	values := make(map[string]string)
+	for _, c := range d.Cells {
+		if c.Value != "" {
+			values[c.Cell] = c.Value
+		}
+	}
+	return values
+}
+
+// diffSheet compares two matched sheets.
+func diffSheet(o, n *inspect.SheetDetails) []change {
+	var changes []change
+	add := func(k kind, item, oldVal, newVal string) {
+		c := change{Op: opChanged, Kind: k, Sheet: n.Name, Item: item,
+			Old: oldVal, New: newVal}
+		switch {
+		case oldVal == "":
+			c.Op = opAdded
+		case newVal == "":
+			c.Op = opRemoved
+		}
+		changes = append(changes, c)
+	}
+
+	oldMarks, newMarks := headerMarks(o), headerMarks(n)
+
+	// セルの値と書式
+	oldCells := make(map[string]*inspect.Cell)
+	for i := range o.Cells {
+		oldCells[o.Cells[i].Cell] = &o.Cells[i]
+	}
+	newCells := make(map[string]*inspect.Cell)
+	for i := range n.Cells {
+		newCells[n.Cells[i].Cell] = &n.Cells[i]
+	}
+	for _, cell := range sortedKeys(oldCells, newCells) {
+		oc, nc := oldCells[cell], newCells[cell]
+		if oc == nil {
+			oc = &inspect.Cell{Cell: cell}
+		}
+		if nc == nil {
+			nc = &inspect.Cell{Cell: cell}
+		}
+		_, oldMarked := oldMarks[cell]
+		_, newMarked := newMarks[cell]
+		// ヘッダの印の変更に値の変更が含まれる場合は、ヘッダとしてのみ報告する
+		inHeader := (oldMarked || newMarked) &&
+			(oldMarked || oc.Value == "") && (newMarked || nc.Value == "")
+		if oc.Value != nc.Value && !inHeader {
+			add(kindValue, cell, oc.Value, nc.Value)
+		}
+		if oldCells[cell] == nil && nc.StyleID == 0 ||
+			newCells[cell] == nil && oc.StyleID == 0 {
+			// 使用されている範囲が変わっただけのセル
+			continue
+		}
+		if !reflect.DeepEqual(oc.Style, nc.Style) {
+			oldStyle, newStyle := describeStyle(oc.Style), describeStyle(nc.Style)
+			if oldStyle == newStyle {
+				oldStyle, newStyle = marshal(oc.Style), marshal(nc.Style)
+			}
+			changes = append(changes, change{Op: opChanged, Kind: kindStyle,
+				Sheet: n.Name, Item: cell, Old: oldStyle, New: newStyle})
+		}
+	}
+
+	// 結合範囲
+	for _, r := range o.MergedRanges {
+		if !slices.Contains(n.MergedRanges, r) {
+			add(kindMerge, r, r, "")
+		}
+	}
+	for _, r := range n.MergedRanges {
+		if !slices.Contains(o.MergedRanges, r) {
+			add(kindMerge, r, "", r)
+		}
+	}
+
+	// ヘッダの印 (ヘッダの印のコメントは、コメントとしては比較しない)
+	for _, cell := range sortedKeys(oldMarks, newMarks) {
+		if oldMarks[cell] != newMarks[cell] {
+			add(kindHeader, cell, oldMarks[cell], newMarks[cell])
+		}
+	}
+
+	// コメント
+	oldComments, newComments := comments(o), comments(n)
+	for _, cell := range sortedKeys(oldComments, newComments) {
+		if _, ok := oldMarks[cell]; ok {
+			continue
+		}
+		if _, ok := newMarks[cell]; ok {
+			continue
+		}
+		if oldComments[cell] != newComments[cell] {
+			add(kindComment, cell, oldComments[cell], newComments[cell])
+		}
+	}
+
+	// ページ設定
+	for _, f := range []struct {
+		name     string
+		old, new any
+	}{
+		{"pageLayout", o.PageLayout, n.PageLayout},
+		{"pageMargins", o.PageMargins, n.PageMargins},
+		{"headerFooter", o.HeaderFooter, n.HeaderFooter},
+	} {
+		ov, nv := reflect.ValueOf(f.old), reflect.ValueOf(f.new)
+		for i := range ov.NumField() {
+			oldVal, newVal := field(ov.Field(i)), field(nv.Field(i))
+			if oldVal != newVal && !sameAsUnset(ov.Field(i), nv.Field(i)) {
+				name, _, _ := strings.Cut(ov.Type().Field(i).Tag.Get("json"), ",")
+				changes = append(changes, change{Op: opChanged,
+					Kind: kindPageSetup, Sheet: n.Name, Item: f.name + "." + name,
+					Old: oldVal, New: newVal})
+			}
+		}
+	}
+	return changes
+}
+
+// sortedKeys returns the cells of both maps in the order of rows and
+// columns.
+func sortedKeys[V any](a, b map[string]V) []string {
+	var cells []string
+	for cell := range a {
+		cells = append(cells, cell)
+	}
+	for cell := range b {
+		if _, ok := a[cell]; !ok {
+			cells = append(cells, cell)
+		}
+	}
+	sortByCoordinates(cells)
+	return cells
+}
+
+// sortByCoordinates sorts the cell names by row and then by column.
+func sortByCoordinates(cells []string) {
+	slices.SortFunc(cells, func(a, b string) int {
+		ac, ar, _ := excelize.CellNameToCoordinates(a)
+		bc, br, _ := excelize.CellNameToCoordinates(b)
+		if ar != br {
+			return ar - br
+		}
+		if ac != bc {
+			return ac - bc
+		}
+		return strings.Compare(a, b)
+	})
+}
+
+// headerMarks returns the header marks of the sheet by cell.
+func headerMarks(d *inspect.SheetDetails) map[string]string {
+	marks := make(map[string]string)
+	for _, m := range d.Summary.HeaderMarks {
+		marks[m.Cell] = fmt.Sprintf("H%d %s", m.Level, m.Value)
+	}
+	return marks
+}
+
+// comments returns the texts of the comments of the sheet by cell.
+func comments(d *inspect.SheetDetails) map[string]string {
+	texts := make(map[string]string)
+	for _, c := range d.Comments {
+		var b strings.Builder
+		b.WriteString(c.Text)
+		for _, p := range c.Paragraph {
+			b.WriteString(p.Text)
+		}
+		texts[c.Cell] = b.String()
+	}
+	return texts
+}
+
+// describeStyle returns the style flags of the style.
+func describeStyle(s *inspect.Style) string {
+	if s == nil {
+		return unset
+	}
+	desc := strings.Join(s.Flags, "|")
+	if desc == "" {
+		desc = "normal"
+	}
+	if !s.FlagsExact {
+		desc += " (+custom)"
+	}
+	return desc
+}
+
+// marshal returns the style as JSON to show the difference of the styles
+// which have the same flags.
+func marshal(s *inspect.Style) string {
+	if s == nil {
+		return unset
+	}
+	b, err := json.Marshal(s)
+	if err != nil {
+		return err.Error()
+	}
+	return string(b)
+}
+
+// unset is the value of an unset property.
+const unset = "unset"
+
+// field formats the value of a property. A nil pointer is unset.
+func field(v reflect.Value) string {
+	if v.Kind() == reflect.Pointer {
+		if v.IsNil() {
+			return unset
+		}
+		v = v.Elem()
+	}
+	return fmt.Sprint(v.Interface())
+}
+
+// sameAsUnset reports whether one of the properties is unset and the other
+// is the zero value, which Excel treats as the same.
+func sameAsUnset(a, b reflect.Value) bool {
+	if a.Kind() != reflect.Pointer {
+		return false
+	}
+	switch {
+	case a.IsNil() && !b.IsNil():
+		return b.Elem().IsZero()
+	case !a.IsNil() && b.IsNil():
+		return a.Elem().IsZero()
+	}
+	return false
+}
@@ -0,0 +1,235 @@
+package main
+
+import (
+	"bytes"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/nonsugar-go/tools/excel"
+	"github.com/nonsugar-go/tools/excel/internal/inspect"
+	"github.com/xuri/excelize/v2"
+)
+
+// book is the content of a workbook for the tests.
+type book struct {
+	sheets    []string
+	values    map[string]map[string]string // シート -> セル -> 値
+	merges    map[string]string            // シート -> 結合範囲
+	comments  map[string]string            // シート -> コメントのあるセル
+	bold      map[string]string            // シート -> 太字のセル
+	landscape string                       // 横向きのシート
+}
+
+// create creates the workbook in the directory.
+func (b *book) create(t *testing.T, filename string) string {
+	t.Helper()
+	filename = filepath.Join(t.TempDir(), filename)
+	e, err := excel.New(filename)
+	if err != nil {
+		t.Fatalf("New: want no error, but %v", err)
+	}
+	f := e.GetFile()
+	for _, sheet := range b.sheets {
+		if err := e.NewSheet(sheet); err != nil {
+			t.Fatalf("NewSheet: want no error, but %v", err)
+		}
+		for cell, v := range b.values[sheet] {
+			if err := f.SetCellStr(sheet, cell, v); err != nil {
+				t.Fatalf("SetCellStr: want no error, but %v", err)
+			}
+		}
+		if r, ok := b.merges[sheet]; ok {
+			cell1, cell2, _ := strings.Cut(r, ":")
+			if err := f.MergeCell(sheet, cell1, cell2); err != nil {
+				t.Fatalf("MergeCell: want no error, but %v", err)
+			}
+		}
+		if cell, ok := b.comments[sheet]; ok {
+			if err := f.AddComment(sheet, excelize.Comment{
+				Cell: cell, Author: "test", Text: "確認済み"}); err != nil {
+				t.Fatalf("AddComment: want no error, but %v", err)
+			}
+		}
+		if cell, ok := b.bold[sheet]; ok {
+			style, err := f.NewStyle(&excelize.Style{
+				Font: &excelize.Font{Bold: true}})
+			if err != nil {
+				t.Fatalf("NewStyle: want no error, but %v", err)
+			}
+			if err := f.SetCellStyle(sheet, cell, cell, style); err != nil {
+				t.Fatalf("SetCellStyle: want no error, but %v", err)
+			}
+		}
+		if sheet == b.landscape {
+			orientation := "landscape"
+			if err := f.SetPageLayout(sheet, &excelize.PageLayoutOptions{
+				Orientation: &orientation}); err != nil {
+				t.Fatalf("SetPageLayout: want no error, but %v", err)
+			}
+		}
+	}
+	if err := e.SaveAndClose(); err != nil {
+		t.Fatalf("SaveAndClose: want no error, but %v", err)
+	}
+	return filename
+}
+
+func TestCompare(t *testing.T) {
+	oldBook := &book{
+		sheets: []string{"概要", "旧手順", "削除"},
+		values: map[string]map[string]string{
+			"概要":  {"B2": "one", "B3": "two", "B4": "three", "C2": "x"},
+			"旧手順": {"D2": "keep1", "D3": "keep2", "D4": "keep3"},
+			"削除":  {"A1": "removed"},
+		},
+		merges:   map[string]string{"概要": "B6:C6"},
+		comments: map[string]string{"概要": "B2"},
+	}
+	newBook := &book{
+		sheets: []string{"概要", "新手順", "追加"},
+		values: map[string]map[string]string{
+			"概要":  {"B2": "one", "B3": "TWO", "C2": "x"},
+			"新手順": {"D2": "keep1", "D3": "keep2", "D4": "changed"},
+			"追加":  {"A1": "added"},
+		},
+		merges:    map[string]string{"概要": "B6:D6"},
+		comments:  map[string]string{"概要": "B3"},
+		bold:      map[string]string{"概要": "C2"},
+		landscape: "概要",
+	}
+	oldName := oldBook.create(t, "old.xlsx")
+	newName := newBook.create(t, "new.xlsx")
+
+	changes, err := compare(oldName, newName)
+	if err != nil {
+		t.Fatalf("compare: want no error, but %v", err)
+	}
+	tests := []change{
+		{Op: opRemoved, Kind: kindSheet, Sheet: "削除", Item: "削除",
+			Old: "削除"},
+		{Op: opChanged, Kind: kindValue, Sheet: "概要", Item: "B3",
+			Old: "two", New: "TWO"},
+		{Op: opRemoved, Kind: kindValue, Sheet: "概要", Item: "B4",
+			Old: "three"},
+		{Op: opChanged, Kind: kindStyle, Sheet: "概要", Item: "C2",
+			Old: "fontFamilyYuGothic|fontSize10", New: "fontBold"},
+		{Op: opRemoved, Kind: kindMerge, Sheet: "概要", Item: "B6:C6",
+			Old: "B6:C6"},
+		{Op: opAdded, Kind: kindMerge, Sheet: "概要", Item: "B6:D6",
+			New: "B6:D6"},
+		{Op: opRemoved, Kind: kindComment, Sheet: "概要", Item: "B2",
+			Old: "確認済み"},
+		{Op: opAdded, Kind: kindComment, Sheet: "概要", Item: "B3",
+			New: "確認済み"},
+		{Op: opChanged, Kind: kindPageSetup, Sheet: "概要",
+			Item: "pageLayout.orientation", Old: "portrait", New: "landscape"},
+		{Op: opRenamed, Kind: kindSheet, Sheet: "新手順", Item: "新手順",
+			Old: "旧手順", New: "新手順"},
+		{Op: opChanged, Kind: kindValue, Sheet: "新手順", Item: "D4",
+			Old: "keep3", New: "changed"},
+		{Op: opAdded, Kind: kindSheet, Sheet: "追加", Item: "追加",
+			New: "追加"},
+	}
+	for _, want := range tests {
+		if !slices.Contains(changes, want) {
+			t.Errorf("compare: want %+v, but not found in %+v", want, changes)
+		}
+	}
+	if len(changes) != len(tests) {
+		t.Errorf("compare: want %d changes, but %d: %+v",
+			len(tests), len(changes), changes)
+	}
+
+	var out bytes.Buffer
+	writeText(&out, oldName, newName, changes)
+	if !strings.Contains(out.String(), "renamed  sheet") {
+		t.Errorf("writeText: want renamed sheet, but %s", out.String())
+	}
+	report := filepath.Join(t.TempDir(), "report.xlsx")
+	if err := writeReport(report, oldName, newName, changes); err != nil {
+		t.Fatalf("writeReport: want no error, but %v", err)
+	}
+	e, err := excel.OpenExcel(report)
+	if err != nil {
+		t.Fatalf("OpenExcel: want no error, but %v", err)
+	}
+	defer e.Close()
+	cfs, err := e.GetFile().GetConditionalFormats(reportSheet)
+	if err != nil {
+		t.Fatalf("GetConditionalFormats: want no error, but %v", err)
+	}
+	if len(cfs) != 1 {
+		t.Errorf("GetConditionalFormats: want 1 range, but %v", cfs)
+	}
+
+	// 同じブック同士は差分なし
+	changes, err = compare(oldName, oldName)
+	if err != nil {
+		t.Fatalf("compare: want no error, but %v", err)
+	}
+	if len(changes) != 0 {
+		t.Errorf("compare: want no changes, but %+v", changes)
+	}
+	if _, err := compare(oldName, "not-found.xlsx"); err == nil {
+		t.Errorf("compare: want error, but %v", err)
+	}
+}
+
+func TestSimilarity(t *testing.T) {
+	sheet := func(values ...string) *inspect.SheetDetails {
+		d := &inspect.SheetDetails{}
+		for i, v := range values {
+			d.Cells = append(d.Cells,
+				inspect.Cell{Cell: "A" + strconv.Itoa(i+1), Value: v})
+		}
+		return d
+	}
+	tests := []struct {
+		name string
+		o, n *inspect.SheetDetails
+		want float64
+	}{
+		{"empty", sheet(), sheet(), 0},
+		{"same", sheet("a", "b"), sheet("a", "b"), 1},
+		{"half", sheet("a", "b"), sheet("a", "c"), 0.5},
+	}
+	for _, tt := range tests {
+		if got := similarity(tt.o, tt.n); got != tt.want {
+			t.Errorf("%s: want %v, but %v", tt.name, tt.want, got)
+		}
+	}
+}
+
+func TestDiffSheet_Header(t *testing.T) {
+	sheet := func(value string, level int) *inspect.SheetDetails {
+		d := &inspect.SheetDetails{Name: "手順",
+			Cells: []inspect.Cell{{Cell: "B2", Value: value}}}
+		if level > 0 {
+			d.Summary.HeaderMarks = []inspect.HeaderMark{
+				{Cell: "B2", Level: level, Value: value}}
+		}
+		return d
+	}
+	tests := []struct {
+		name string
+		o, n *inspect.SheetDetails
+		want []change
+	}{
+		{"header text", sheet("概要", 2), sheet("はじめに", 2), []change{
+			{Op: opChanged, Kind: kindHeader, Sheet: "手順", Item: "B2",
+				Old: "H2 概要", New: "H2 はじめに"}}},
+		{"header removed", sheet("概要", 2), sheet("本文", 0), []change{
+			{Op: opChanged, Kind: kindValue, Sheet: "手順", Item: "B2",
+				Old: "概要", New: "本文"},
+			{Op: opRemoved, Kind: kindHeader, Sheet: "手順", Item: "B2",
+				Old: "H2 概要"}}},
+	}
+	for _, tt := range tests {
+		if got := diffSheet(tt.o, tt.n); !slices.Equal(got, tt.want) {
+			t.Errorf("%s: want %+v, but %+v", tt.name, tt.want, got)
+		}
+	}
+}
@@ -0,0 +1,107 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log/slog"
+	"os"
+
+	"github.com/nonsugar-go/tools/excel"
+	"github.com/nonsugar-go/tools/excel/internal/inspect"
+)
+
+const logFilename = "xlsxdiff.log"
+
+// 終了コード (diff コマンドと同じ)
+const (
+	exitSame  = 0 // 差分なし
+	exitDiff  = 1 // 差分あり
+	exitError = 2 // エラー
+)
+
+func main() {
+	logFile, err := os.OpenFile(logFilename,
+		os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		panic(err)
+	}
+	defer logFile.Close()
+	slog.SetDefault(slog.New(slog.NewJSONHandler(logFile,
+		&slog.HandlerOptions{
+			AddSource: true,
+		})))
+
+	var oldName, newName, out string
+	flag.StringVar(&oldName, "old", "", "変更前の Excel ファイル (*.xlsx)")
+	flag.StringVar(&newName, "new", "", "変更後の Excel ファイル (*.xlsx)")
+	flag.StringVar(&out, "out", "",
+		"Excel report file (*.xlsx) instead of the text output")
+	flag.Parse()
+	if oldName == "" || newName == "" {
+		slog.Error("Excel ファイルが指定されていません")
+		fmt.Println("Excel ファイルが指定されていません")
+		os.Exit(exitError)
+	}
+
+	changes, err := compare(oldName, newName)
+	if err != nil {
+		slog.Error("比較できません", "error", err)
+		fmt.Printf("比較できません: %v\n", err)
+		os.Exit(exitError)
+	}
+	if out != "" {
+		if err := writeReport(out, oldName, newName, changes); err != nil {
+			slog.Error("レポートが出力できません", "error", err)
+			fmt.Printf("レポートが出力できません: %v\n", err)
+			os.Exit(exitError)
+		}
+	} else {
+		writeText(os.Stdout, oldName, newName, changes)
+	}
+	if len(changes) > 0 {
+		os.Exit(exitDiff)
+	}
+	os.Exit(exitSame)
+}
+
+// compare opens the workbooks and compares every sheet.
+func compare(oldName, newName string) ([]change, error) {
+	oldInfo, err := collect(oldName)
+	if err != nil {
+		return nil, err
+	}
+	newInfo, err := collect(newName)
+	if err != nil {
+		return nil, err
+	}
+	for _, info := range []*inspect.Info{oldInfo, newInfo} {
+		for _, w := range info.Warnings {
+			slog.Warn(w, "file", info.File)
+		}
+		for _, d := range info.Sheets {
+			for _, w := range d.Warnings {
+				slog.Warn(w, "file", info.File, "sheet", d.Name)
+			}
+		}
+	}
+	return diff(oldInfo, newInfo), nil
+}
+
+// collect collects the information of every cell of every sheet.
+func collect(filename string) (*inspect.Info, error) {
+	e, err := excel.OpenExcel(filename)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if err := e.Close(); err != nil {
+			slog.Error("Excel ファイルが閉じられません", "error", err)
+		}
+	}()
+	return inspect.Collect(e, filename, inspect.Options{
+		AllSheets: true,
+		ColMin:    1,
+		RowMin:    1,
+		AllCells:  true,
+	})
+}
@@ -0,0 +1,112 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/nonsugar-go/tools/excel"
+	"github.com/nonsugar-go/tools/excel/dataframe"
+	"github.com/xuri/excelize/v2"
+)
+
+// reportSheet is the sheet name of the Excel report.
+const reportSheet = "差分"
+
+// writeText writes the changes as a table.
+func writeText(out io.Writer, oldName, newName string, changes []change) {
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(w, "--- %s\n", oldName)
+	fmt.Fprintf(w, "+++ %s\n", newName)
+	if len(changes) == 0 {
+		fmt.Fprintln(w, "no differences")
+		w.Flush()
+		return
+	}
+	fmt.Fprint(w, "OP\tKIND\tSHEET\tITEM\tOLD\tNEW\t\n")
+	for _, c := range changes {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\t\n",
+			c.Op, c.Kind, c.Sheet, c.Item, quote(c.Old), quote(c.New))
+	}
+	fmt.Fprintf(w, "%d changes\n", len(changes))
+	w.Flush()
+}
+
+// quote quotes the value if it contains control characters such as
+// newlines, which break the table.
+func quote(s string) string {
+	if strings.ContainsFunc(s, func(r rune) bool { return r < ' ' }) {
+		return fmt.Sprintf("%q", s)
+	}
+	return s
+}
+
+// writeReport writes the changes to the sheet of a new workbook, and
+// highlights the rows by operation.
+func writeReport(filename, oldName, newName string, changes []change) error {
+	e, err := excel.New(filename)
+	if err != nil {
+		return err
+	}
+	if err := writeReportSheet(e, oldName, newName, changes); err != nil {
+		_ = e.Close()
+		return err
+	}
+	return e.SaveAndClose()
+}
+
+// writeReportSheet writes the changes to the report sheet.
+func writeReportSheet(e *excel.Excel, oldName, newName string,
+	changes []change) error {
+	if err := e.NewSheet(reportSheet, excel.SheetTypeNormal); err != nil {
+		return err
+	}
+	if err := e.CR(2).LF().SetVal("変更前: " + oldName); err != nil {
+		return err
+	}
+	if err := e.CR(2).LF().SetVal("変更後: " + newName); err != nil {
+		return err
+	}
+	df := dataframe.New("B", "操作", "E", "種類", "H", "シート", "L", "項目",
+		"P", "変更前", "Y", "変更後")
+	for _, c := range changes {
+		df.Add(string(c.Op), string(c.Kind), c.Sheet, c.Item, c.Old, c.New)
+	}
+	if err := e.WriteDFWithOptions(df, excel.WriteDFOptions{Wrap: true}); err != nil {
+		return err
+	}
+	if len(changes) == 0 {
+		return nil
+	}
+
+	// 操作ごとに行を色分けする (見出しの行を除く)
+	cell1, cell2, _ := strings.Cut(e.LastDFRange(), ":")
+	col1, row1, err := excelize.CellNameToCoordinates(cell1)
+	if err != nil {
+		return err
+	}
+	first, err := excel.CoordinatesToCellName(col1, row1+1)
+	if err != nil {
+		return err
+	}
+	rangeRef := first + ":" + cell2
+	rule := func(o op) string {
+		return fmt.Sprintf(`$%s="%s"`, first, o)
+	}
+	if err := e.HighlightWhen(rangeRef, rule(opAdded),
+		excel.NewStyle().Fill("00B0F0")); err != nil {
+		return err
+	}
+	if err := e.HighlightWhen(rangeRef, rule(opRemoved),
+		excel.NewStyle().Fill("A6A6A6")); err != nil {
+		return err
+	}
+	for _, o := range []op{opChanged, opRenamed} {
+		if err := e.HighlightWhen(rangeRef, rule(o),
+			excel.NewStyle().Fill("FFFF00")); err != nil {
+			return err
+		}
+	}
+	return nil
+}
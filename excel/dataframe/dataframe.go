@@ -1,6 +1,10 @@
 package dataframe
 
-import "github.com/xuri/excelize/v2"
+import (
+	"strings"
+
+	"github.com/xuri/excelize/v2"
+)
 
 // Header represents column headers.
 type Header struct {
@@ -8,6 +12,8 @@ type Header struct {
 	ColumnName string
 	Col        int
 	IsArray    bool
+	Width      int      // 列が占めるセルの数 (0 は未指定)
+	Allowed    []string // 入力できる値の一覧 (nil は制限なし)
 }
 
 // Record represents a single row of data.
@@ -58,3 +64,70 @@ func (df *DataFrame) Add(record ...string) *DataFrame {
 	df.Records = append(df.Records, Record(record))
 	return df
 }
+
+// SetArray marks the columns with the specified names as IsArray columns.
+//
+// Example:
+//
+//	df := New("B", "ID", "E", "送信元", "K", "宛先").SetArray("送信元", "宛先")
+func (df *DataFrame) SetArray(names ...string) *DataFrame {
+	for i := range df.Headers {
+		for _, name := range names {
+			if df.Headers[i].Name == name {
+				df.Headers[i].IsArray = true
+			}
+		}
+	}
+	return df
+}
+
+// SetAllowed declares the values allowed in the column with the specified
+// name. WriteDF adds them to the cells as a drop-down list.
+//
+// Example:
+//
+//	df := New("B", "ID", "Q", "動作").SetAllowed("動作", "accept", "deny")
+func (df *DataFrame) SetAllowed(name string, values ...string) *DataFrame {
+	for i := range df.Headers {
+		if df.Headers[i].Name == name {
+			df.Headers[i].Allowed = values
+		}
+	}
+	return df
+}
+
+// AddGroup adds a record whose cells may have multiple values.
+//
+// The record is expanded into as many rows as the longest IsArray cell.
+// The first row holds the first value of each cell, and the following
+// rows hold the rest of the values of the IsArray cells with the other
+// cells left blank, which is the layout TBorderHHeaderG merges into a group.
+// Multiple values of a cell which is not an IsArray column are joined with
+// a newline.
+//
+// Example:
+//
+//	df.AddGroup([]string{"1"}, []string{"host1", "host2"}, []string{"web1"})
+func (df *DataFrame) AddGroup(cells ...[]string) *DataFrame {
+	rows := 1
+	for i, values := range cells {
+		if i < len(df.Headers) && df.Headers[i].IsArray {
+			rows = max(rows, len(values))
+		}
+	}
+	for r := range rows {
+		record := make(Record, len(cells))
+		for i, values := range cells {
+			switch {
+			case i < len(df.Headers) && df.Headers[i].IsArray:
+				if r < len(values) {
+					record[i] = values[r]
+				}
+			case r == 0:
+				record[i] = strings.Join(values, "\n")
+			}
+		}
+		df.Records = append(df.Records, record)
+	}
+	return df
+}
@@ -0,0 +1,139 @@
+package dataframe
+
+import (
+	"reflect"
+	"testing"
+)
+
+type policy struct {
+	ID      string   `excel:"col=B,name=ID,width=3"`
+	Src     []string `excel:"name=送信元,width=6"`
+	Dst     []string `excel:"name=宛先"`
+	Action  string   `excel:"col=Q,name=動作,allowed=accept|deny"`
+	Port    int      `excel:"name=ポート"`
+	Enabled bool
+	memo    string
+	Ignored string `excel:"-"`
+}
+
+func TestFromStructs(t *testing.T) {
+	policies := []policy{
+		{ID: "1", Src: []string{"host1", "host2"}, Dst: []string{"web1"},
+			Action: "accept", Port: 443, Enabled: true},
+		{ID: "2", Dst: []string{"web1", "web2", "web3"},
+			Action: "deny", Port: 80},
+	}
+	df, err := FromStructs(policies)
+	if err != nil {
+		t.Fatalf("FromStructs: want no error, but %v", err)
+	}
+	wantHeaders := []Header{
+		{Name: "ID", ColumnName: "B", Col: 2, Width: 3},
+		{Name: "送信元", ColumnName: "E", Col: 5, IsArray: true, Width: 6},
+		{Name: "宛先", ColumnName: "K", Col: 11, IsArray: true},
+		{Name: "動作", ColumnName: "Q", Col: 17,
+			Allowed: []string{"accept", "deny"}},
+		{Name: "ポート", ColumnName: "U", Col: 21},
+		{Name: "Enabled", ColumnName: "Y", Col: 25},
+	}
+	if !reflect.DeepEqual(df.Headers, wantHeaders) {
+		t.Errorf("Headers: want %v, but %v", wantHeaders, df.Headers)
+	}
+	wantRecords := Records{
+		{"1", "host1", "web1", "accept", "443", "true"},
+		{"", "host2", "", "", "", ""},
+		{"2", "", "web1", "deny", "80", "false"},
+		{"", "", "web2", "", "", ""},
+		{"", "", "web3", "", "", ""},
+	}
+	if !reflect.DeepEqual(df.Records, wantRecords) {
+		t.Errorf("Records: want %v, but %v", wantRecords, df.Records)
+	}
+
+	var got []policy
+	if err := df.ToStructs(&got); err != nil {
+		t.Fatalf("ToStructs: want no error, but %v", err)
+	}
+	if !reflect.DeepEqual(got, policies) {
+		t.Errorf("ToStructs: want %v, but %v", policies, got)
+	}
+}
+
+func TestFromStructs_Error(t *testing.T) {
+	type arrayFirst struct {
+		Src []string
+	}
+	type badTag struct {
+		ID string `excel:"color=red"`
+	}
+	type badType struct {
+		ID  string
+		Map map[string]string
+	}
+	tests := []struct {
+		name  string
+		input any
+	}{
+		{"nil", nil},
+		{"not a slice", policy{}},
+		{"not a struct", []string{"a"}},
+		{"first column is a slice", []arrayFirst{}},
+		{"unknown tag option", []badTag{}},
+		{"unsupported type", []badType{}},
+		{"blank first column", []policy{{ID: "1"}, {Action: "deny"}}},
+		{"blank slice element", []policy{{ID: "1", Src: []string{"host1", ""}}}},
+	}
+	for _, tt := range tests {
+		if _, err := FromStructs(tt.input); err == nil {
+			t.Errorf("%s: want error, but %v", tt.name, err)
+		}
+	}
+}
+
+func TestDataFrame_ToStructs(t *testing.T) {
+	df := New("B", "ID", "Q", "ポート").
+		Add("1", "443").
+		Add("2", "x")
+	var got []*policy
+	if err := df.ToStructs(&got); err == nil {
+		t.Errorf("want error, but %v", err)
+	}
+	if err := df.ToStructs(got); err == nil {
+		t.Errorf("want error, but %v", err)
+	}
+
+	// 続きの行に配列でない列の値がある場合は、失わずにエラーとする
+	df = New("B", "ID", "Q", "動作").
+		Add("1", "accept").
+		Add("", "deny")
+	var policies []policy
+	if err := df.ToStructs(&policies); err == nil {
+		t.Errorf("continuation row: want error, but %v", err)
+	}
+}
+
+func TestDataFrame_AddGroup(t *testing.T) {
+	df := New("B", "ID", "E", "送信元", "K", "宛先", "Q", "備考").
+		SetArray("送信元", "宛先").
+		AddGroup([]string{"1"}, []string{"host1", "host2"}, []string{"web1"},
+			[]string{"1行目", "2行目"}).
+		AddGroup([]string{"2"}, nil, []string{"web1", "web2", "web3"}, nil)
+	if !df.Headers[1].IsArray || !df.Headers[2].IsArray ||
+		df.Headers[0].IsArray || df.Headers[3].IsArray {
+		t.Errorf("SetArray: unexpected headers %v", df.Headers)
+	}
+	df.SetAllowed("備考", "a", "b")
+	if !reflect.DeepEqual(df.Headers[3].Allowed, []string{"a", "b"}) {
+		t.Errorf("SetAllowed: unexpected headers %v", df.Headers)
+	}
+	want := Records{
+		{"1", "host1", "web1", "1行目\n2行目"},
+		{"", "host2", "", ""},
+		{"2", "", "web1", ""},
+		{"", "", "web2", ""},
+		{"", "", "web3", ""},
+	}
+	if !reflect.DeepEqual(df.Records, want) {
+		t.Errorf("want %q, but %q", want, df.Records)
+	}
+}
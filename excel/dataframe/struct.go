@@ -0,0 +1,397 @@
+package dataframe
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/xuri/excelize/v2"
+)
+
+const (
+	tagName = "excel"
+
+	// defaultFirstColumn is the column of the first field when no "col" is
+	// specified. It matches the column where WriteDF starts a table.
+	defaultFirstColumn = "B"
+	// defaultSpan is the number of cells a column occupies when
+	// neither "col" of the next field nor "width" is specified.
+	defaultSpan = 4
+)
+
+// field maps a struct field to a column header.
+type field struct {
+	index  int
+	header Header
+}
+
+// parseTag parses a struct tag such as `excel:"col=B,name=ID,width=10"`.
+// The allowed values are separated by "|", such as "allowed=accept|deny".
+func parseTag(tag string, h *Header) error {
+	for _, opt := range strings.Split(tag, ",") {
+		opt = strings.TrimSpace(opt)
+		if opt == "" {
+			continue
+		}
+		key, value, ok := strings.Cut(opt, "=")
+		if !ok {
+			return fmt.Errorf("invalid tag option %q: want key=value", opt)
+		}
+		switch strings.TrimSpace(key) {
+		case "col":
+			col, err := excelize.ColumnNameToNumber(strings.TrimSpace(value))
+			if err != nil {
+				return fmt.Errorf("invalid tag option %q: %w", opt, err)
+			}
+			h.ColumnName, h.Col = strings.ToUpper(strings.TrimSpace(value)), col
+		case "name":
+			h.Name = value
+		case "width":
+			width, err := strconv.Atoi(strings.TrimSpace(value))
+			if err != nil || width < 1 {
+				return fmt.Errorf(
+					"invalid tag option %q: width must be a positive integer",
+					opt)
+			}
+			h.Width = width
+		case "allowed":
+			h.Allowed = strings.Split(value, "|")
+		default:
+			return fmt.Errorf("unknown tag option %q", opt)
+		}
+	}
+	return nil
+}
+
+// parseFields returns the columns of the struct type t.
+// Unexported fields and fields tagged with `excel:"-"` are skipped.
+func parseFields(t reflect.Type) ([]field, error) {
+	fields := make([]field, 0, t.NumField())
+	for i := range t.NumField() {
+		sf := t.Field(i)
+		tag := sf.Tag.Get(tagName)
+		if !sf.IsExported() || tag == "-" {
+			continue
+		}
+		h := Header{Name: sf.Name}
+		if err := parseTag(tag, &h); err != nil {
+			return nil, fmt.Errorf("field %s: %w", sf.Name, err)
+		}
+		ft := sf.Type
+		if ft.Kind() == reflect.Slice {
+			h.IsArray = true
+			ft = ft.Elem()
+		}
+		if ft.Kind() == reflect.Pointer {
+			ft = ft.Elem()
+		}
+		if !isScalar(ft.Kind()) {
+			return nil, fmt.Errorf("field %s: unsupported type %s",
+				sf.Name, sf.Type)
+		}
+		fields = append(fields, field{index: i, header: h})
+	}
+	if len(fields) == 0 {
+		return nil, fmt.Errorf("struct %s has no exported fields", t)
+	}
+	if fields[0].header.IsArray {
+		// 1列目はグループの区切りとして使うので、配列形式にはできない
+		return nil, fmt.Errorf(
+			"field %s: the first column must not be a slice",
+			t.Field(fields[0].index).Name)
+	}
+
+	// 列が指定されていない場合、直前の列と幅から求める
+	next := 0
+	for i := range fields {
+		h := &fields[i].header
+		if h.Col == 0 {
+			if next == 0 {
+				next, _ = excelize.ColumnNameToNumber(defaultFirstColumn)
+			}
+			name, err := excelize.ColumnNumberToName(next)
+			if err != nil {
+				return nil, fmt.Errorf("field %s: %w",
+					t.Field(fields[i].index).Name, err)
+			}
+			h.ColumnName, h.Col = name, next
+		}
+		span := h.Width
+		if span == 0 {
+			span = defaultSpan
+		}
+		next = h.Col + span
+	}
+	return fields, nil
+}
+
+// isScalar reports whether a value of kind k can be stored in a cell.
+func isScalar(k reflect.Kind) bool {
+	switch k {
+	case reflect.String, reflect.Bool,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32,
+		reflect.Uint64, reflect.Float32, reflect.Float64:
+		return true
+	}
+	return false
+}
+
+// structType returns the struct type of the elements of a slice type.
+func structType(t reflect.Type) (reflect.Type, error) {
+	if t.Kind() != reflect.Slice && t.Kind() != reflect.Array {
+		return nil, fmt.Errorf("want a slice of structs, but %s", t)
+	}
+	et := t.Elem()
+	if et.Kind() == reflect.Pointer {
+		et = et.Elem()
+	}
+	if et.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("want a slice of structs, but %s", t)
+	}
+	return et, nil
+}
+
+// formatValue converts a scalar value to its cell representation.
+func formatValue(v reflect.Value) string {
+	if v.Kind() == reflect.Pointer {
+		if v.IsNil() {
+			return ""
+		}
+		v = v.Elem()
+	}
+	switch v.Kind() {
+	case reflect.String:
+		return v.String()
+	case reflect.Bool:
+		return strconv.FormatBool(v.Bool())
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32,
+		reflect.Int64:
+		return strconv.FormatInt(v.Int(), 10)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32,
+		reflect.Uint64:
+		return strconv.FormatUint(v.Uint(), 10)
+	case reflect.Float32:
+		return strconv.FormatFloat(v.Float(), 'f', -1, 32)
+	case reflect.Float64:
+		return strconv.FormatFloat(v.Float(), 'f', -1, 64)
+	}
+	return fmt.Sprint(v.Interface())
+}
+
+// parseValue sets s to the scalar value v.
+func parseValue(s string, v reflect.Value) error {
+	if v.Kind() == reflect.Pointer {
+		if s == "" {
+			v.SetZero()
+			return nil
+		}
+		p := reflect.New(v.Type().Elem())
+		if err := parseValue(s, p.Elem()); err != nil {
+			return err
+		}
+		v.Set(p)
+		return nil
+	}
+	if s == "" && v.Kind() != reflect.String {
+		v.SetZero()
+		return nil
+	}
+	switch v.Kind() {
+	case reflect.String:
+		v.SetString(s)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(s)
+		if err != nil {
+			return err
+		}
+		v.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32,
+		reflect.Int64:
+		n, err := strconv.ParseInt(s, 10, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetInt(n)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32,
+		reflect.Uint64:
+		n, err := strconv.ParseUint(s, 10, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetUint(n)
+	case reflect.Float32, reflect.Float64:
+		f, err := strconv.ParseFloat(s, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetFloat(f)
+	default:
+		return fmt.Errorf("unsupported type %s", v.Type())
+	}
+	return nil
+}
+
+// FromStructs creates a DataFrame from a slice of structs.
+//
+// The columns are read from struct tags. A field without a "col" option is
+// placed right after the previous column, and a field without a tag uses its
+// field name as the column name. Slice fields become IsArray columns, and
+// each struct is added with AddGroup.
+//
+// Example:
+//
+//	type Policy struct {
+//		ID     string   `excel:"col=B,name=ID,width=3"`
+//		Src    []string `excel:"col=E,name=送信元"`
+//		Action string   `excel:"col=Q,name=動作"`
+//		Memo   string   `excel:"-"`
+//	}
+//	df, err := FromStructs([]Policy{{"1", []string{"host1", "host2"}, "accept", ""}})
+func FromStructs(slice any) (*DataFrame, error) {
+	rv := reflect.ValueOf(slice)
+	if !rv.IsValid() {
+		return nil, errors.New("FromStructs: nil value")
+	}
+	et, err := structType(rv.Type())
+	if err != nil {
+		return nil, fmt.Errorf("FromStructs: %w", err)
+	}
+	fields, err := parseFields(et)
+	if err != nil {
+		return nil, fmt.Errorf("FromStructs: %w", err)
+	}
+	df := &DataFrame{Headers: make([]Header, 0, len(fields))}
+	for _, f := range fields {
+		df.Headers = append(df.Headers, f.header)
+	}
+	for i := range rv.Len() {
+		sv := rv.Index(i)
+		if sv.Kind() == reflect.Pointer {
+			if sv.IsNil() {
+				continue
+			}
+			sv = sv.Elem()
+		}
+		cells := make([][]string, len(fields))
+		for j, f := range fields {
+			fv := sv.Field(f.index)
+			name := et.Field(f.index).Name
+			if !f.header.IsArray {
+				cells[j] = []string{formatValue(fv)}
+				if j == 0 && cells[j][0] == "" {
+					// 空欄の場合は直前の構造体の続きの行になってしまう
+					return nil, fmt.Errorf(
+						"FromStructs: element %d, field %s: the first column must not be blank",
+						i, name)
+				}
+				continue
+			}
+			cells[j] = make([]string, fv.Len())
+			for k := range fv.Len() {
+				cells[j][k] = formatValue(fv.Index(k))
+				if cells[j][k] == "" {
+					// 空欄のセルは ToStructs で読み戻せない
+					return nil, fmt.Errorf(
+						"FromStructs: element %d, field %s[%d]: a slice element must not be blank",
+						i, name, k)
+				}
+			}
+		}
+		df.AddGroup(cells...)
+	}
+	return df, nil
+}
+
+// ToStructs stores the records of the DataFrame in the slice pointed to
+// by out, which is the reverse of FromStructs.
+//
+// Columns are matched to fields by the column name. A record whose first
+// column is blank is treated as a continuation row, and its values of
+// IsArray columns are appended to the slice fields of the previous struct.
+// Blank cells of IsArray columns are skipped. Therefore FromStructs rejects
+// a blank first column and blank slice elements, and ToStructs returns an
+// error if a continuation row has a value in a column which is not IsArray,
+// instead of losing it.
+//
+// Example:
+//
+//	var policies []Policy
+//	err := df.ToStructs(&policies)
+func (df *DataFrame) ToStructs(out any) error {
+	rv := reflect.ValueOf(out)
+	if rv.Kind() != reflect.Pointer || rv.IsNil() ||
+		rv.Elem().Kind() != reflect.Slice {
+		return errors.New("ToStructs: want a non-nil pointer to a slice")
+	}
+	sliceV := rv.Elem()
+	et, err := structType(sliceV.Type())
+	if err != nil {
+		return fmt.Errorf("ToStructs: %w", err)
+	}
+	fields, err := parseFields(et)
+	if err != nil {
+		return fmt.Errorf("ToStructs: %w", err)
+	}
+
+	// 列名からレコード内の位置を求める
+	columns := make(map[string]int, len(df.Headers))
+	for i, h := range df.Headers {
+		columns[h.Name] = i
+	}
+	isPtr := sliceV.Type().Elem().Kind() == reflect.Pointer
+
+	var cur reflect.Value // 処理中の構造体
+	flush := func() {
+		if !cur.IsValid() {
+			return
+		}
+		if isPtr {
+			sliceV.Set(reflect.Append(sliceV, cur.Addr()))
+		} else {
+			sliceV.Set(reflect.Append(sliceV, cur))
+		}
+	}
+	for n, record := range df.Records {
+		isContinued := cur.IsValid() && (len(record) == 0 || record[0] == "")
+		if !isContinued {
+			flush()
+			cur = reflect.New(et).Elem()
+		}
+		for _, f := range fields {
+			i, ok := columns[f.header.Name]
+			if !ok || i >= len(record) {
+				continue
+			}
+			fv := cur.Field(f.index)
+			if f.header.IsArray {
+				if record[i] == "" {
+					continue
+				}
+				ev := reflect.New(fv.Type().Elem()).Elem()
+				if err := parseValue(record[i], ev); err != nil {
+					return fmt.Errorf("ToStructs: record %d, column %s: %w",
+						n, f.header.Name, err)
+				}
+				fv.Set(reflect.Append(fv, ev))
+				continue
+			}
+			if isContinued {
+				if record[i] != "" {
+					return fmt.Errorf(
+						"ToStructs: record %d, column %s: a continuation row must be blank except IsArray columns",
+						n, f.header.Name)
+				}
+				continue
+			}
+			if err := parseValue(record[i], fv); err != nil {
+				return fmt.Errorf("ToStructs: record %d, column %s: %w",
+					n, f.header.Name, err)
+			}
+		}
+	}
+	flush()
+	return nil
+}
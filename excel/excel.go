@@ -7,6 +7,7 @@ import (
 	"log"
 	"math"
 	"sort"
+	"strings"
 
 	"github.com/mattn/go-runewidth"
 	"github.com/xuri/excelize/v2"
@@ -58,6 +59,18 @@ type Excel struct {
 	fontSize     float64
 	cellStyleIDs map[cellStyle]int
 	cellStyleMap map[string]cellStyle
+
+	// Range of the last DataFrame written by WriteDF
+	dfRange string
+
+	// The workbook is created by NewFromTemplate
+	fromTemplate bool
+
+	// Captions of the figures and the tables written by Caption
+	captions []caption
+
+	// Progress hook set by SetProgress
+	progress ProgressFunc
 }
 
 // New creates an Excel instance with the given filename.
@@ -113,6 +126,16 @@ func OpenExcel(book string) (*Excel, error) {
 	return e, nil
 }
 
+// LastDFRange returns the range of the last DataFrame written by WriteDF,
+// such as "B3:AG10". It returns "" if no DataFrame has been written.
+//
+// The range of a TOMATO table contains merged cells, so AddTable rejects
+// it. To write a DataFrame as an Excel table, use WriteDFOptions.TableName,
+// whose range has a single column per header.
+func (e *Excel) LastDFRange() string {
+	return e.dfRange
+}
+
 // GetFile returns excelize.File.
 func (e *Excel) GetFile() *excelize.File {
 	return e.f
@@ -139,26 +162,36 @@ func (e *Excel) SaveAndClose() error {
 				e.sheet, err)
 		}
 	}
-	if _, err := e.f.GetSheetIndex("目次"); err == nil {
+	// 図表の番号を付け、相互参照を置き換える
+	if err := e.resolveCaptions(); err != nil {
+		return fmt.Errorf("error resolving captions: %w", err)
+	}
+	if idx, err := e.f.GetSheetIndex(tocSheet); err == nil && idx != -1 {
 		// シート「目次」がある場合、目次を作成する
-		e.sheet = "目次"
+		e.sheet = tocSheet
 		e.Col, e.Row = 10, 5
 		if err := e.MakeTOC(); err != nil {
 			return fmt.Errorf("error creating TOC on '%s': %v", e.sheet, err)
 		}
+		// 目次の下に図目次と表目次を作成する
+		if err := e.makeListOfCaptions(); err != nil {
+			return fmt.Errorf("error creating list of captions on '%s': %v",
+				e.sheet, err)
+		}
 		if err := e.applyCellStyle(); err != nil {
 			return fmt.Errorf("operation failed on the previous sheet: %s: %w",
 				e.sheet, err)
 		}
 	}
-	if _, err := e.f.GetSheetIndex("表紙"); err == nil {
+	if idx, err := e.f.GetSheetIndex(coverSheet); err == nil && idx != -1 {
 		// シート「表紙」がある場合、アクティブにする
-		e.sheet = "表紙"
+		e.sheet = coverSheet
 		if err := e.SetActiveSheet(); err != nil {
 			return fmt.Errorf("failed to activate sheet '%s': %v",
 				e.sheet, err)
 		}
 	}
+	e.reportProgress(0, 0, "SaveAndClose: "+e.book)
 	if err := e.f.SaveAs(e.book); err != nil {
 		return fmt.Errorf(
 			"cannot save excel book: %s: %w",
@@ -174,7 +207,8 @@ func (e *Excel) SaveAndClose() error {
 func (e *Excel) NewSheet(sheet string, typ ...SheetType) error {
 	isFoundDefaultSheet := false
 	if e.sheet == "" {
-		isFoundDefaultSheet = true
+		// テンプレートのシートは削除しない
+		isFoundDefaultSheet = !e.fromTemplate
 	} else {
 		// 直前のシートに対する処理
 		if err := e.applyCellStyle(); err != nil {
@@ -184,7 +218,7 @@ func (e *Excel) NewSheet(sheet string, typ ...SheetType) error {
 	}
 	title := sheet
 	if len(typ) > 0 && typ[0] == SheetTypeCover {
-		sheet = "表紙"
+		sheet = coverSheet
 	}
 	_, err := e.f.NewSheet(sheet)
 	if err != nil {
@@ -457,35 +491,291 @@ func (e *Excel) SetHeader(headers []Header) error {
 	return nil
 }
 
-// AddTable adds an Excel table.
-func (e *Excel) AddTable(table string) error {
-	rows, err := e.f.GetRows(e.sheet)
-	if err != nil {
-		return err
+// SubtotalFunc is the function of a column in the subtotal row under a
+// table. See TableOptions.Subtotals.
+type SubtotalFunc int
+
+const (
+	SubtotalNone      SubtotalFunc = iota // なし
+	SubtotalSum                           // 合計
+	SubtotalAverage                       // 平均
+	SubtotalCount                         // 個数
+	SubtotalCountNums                     // 数値の個数
+	SubtotalMax                           // 最大
+	SubtotalMin                           // 最小
+	SubtotalStdDev                        // 標本標準偏差
+	SubtotalVar                           // 標本分散
+)
+
+// subtotal returns the function number of SUBTOTAL which ignores hidden rows.
+func (t SubtotalFunc) subtotal() (int, bool) {
+	switch t {
+	case SubtotalSum:
+		return 109, true
+	case SubtotalAverage:
+		return 101, true
+	case SubtotalCount:
+		return 103, true
+	case SubtotalCountNums:
+		return 102, true
+	case SubtotalMax:
+		return 104, true
+	case SubtotalMin:
+		return 105, true
+	case SubtotalStdDev:
+		return 107, true
+	case SubtotalVar:
+		return 110, true
+	}
+	return 0, false
+}
+
+// TableOptions specifies an Excel table (ListObject) added by AddTable.
+// The zero value is the table of AddTable without options.
+type TableOptions struct {
+	// Range is the range of the table including the header row,
+	// such as "B3:F10". Default is from A1 to the last used cell.
+	Range string
+	// StyleName is the built-in table style name, such as
+	// "TableStyleMedium2". Default is "TableStyleLight16".
+	StyleName string
+	// ColumnNames overwrites the header cells from the left.
+	ColumnNames []string
+	// HideHeaderRow hides the header row and its filter buttons.
+	HideHeaderRow bool
+	// HideRowStripes disables the banded rows.
+	HideRowStripes bool
+	// ShowColumnStripes enables the banded columns.
+	ShowColumnStripes bool
+	// ShowFirstColumn and ShowLastColumn emphasize the first and the last
+	// column.
+	ShowFirstColumn, ShowLastColumn bool
+	// Subtotals writes a row of SUBTOTAL formulas just below the table,
+	// with the function of each column from the left, so rows hidden by
+	// the filters are excluded. The row is plain cells under the table,
+	// not the totals row of the table: it is not styled by StyleName and
+	// does not follow the table when the table is moved or resized.
+	Subtotals []SubtotalFunc
+	// SubtotalLabel is written in the first column of the subtotal row
+	// when the column has no function. Default is "合計".
+	SubtotalLabel string
+}
+
+// AddTable adds an Excel table. The range must not contain merged cells,
+// and ColumnNames cannot be used with HideHeaderRow. The name follows the
+// rules of excelize and must not be used by another table. The name and
+// the options are checked before the sheet is changed.
+//
+// Example:
+//
+//	err := e.AddTable("Table1")
+//	err := e.AddTable("Policies", TableOptions{
+//		Range:     "B3:D10",
+//		StyleName: "TableStyleMedium2",
+//		Subtotals: []SubtotalFunc{SubtotalCount, SubtotalNone, SubtotalSum},
+//	})
+func (e *Excel) AddTable(table string, opts ...TableOptions) error {
+	var opt TableOptions
+	if len(opts) > 0 {
+		opt = opts[0]
+	}
+	rangeRef := opt.Range
+	if rangeRef == "" {
+		rows, err := e.f.GetRows(e.sheet)
+		if err != nil {
+			return err
+		}
+		lastRow := len(rows)
+		lastCol := 0
+		if lastRow > 0 {
+			lastCol = len(rows[0])
+		}
+		cellName, err := excelize.CoordinatesToCellName(lastCol, lastRow)
+		if err != nil {
+			return fmt.Errorf("AddTable: %w", err)
+		}
+		rangeRef = "A1:" + cellName
 	}
-	lastRow := len(rows)
-	lastCol := 0
-	if lastRow > 0 {
-		lastCol = len(rows[0])
+	opt.Range = rangeRef
+	if err := e.checkTable(table, opt); err != nil {
+		return fmt.Errorf("AddTable: %w", err)
 	}
-	cellName, err := excelize.CoordinatesToCellName(lastCol, lastRow)
+	col1, row1, _, row2, err := rangeToCoordinates(rangeRef)
 	if err != nil {
 		return fmt.Errorf("AddTable: %w", err)
 	}
+
+	for i, name := range opt.ColumnNames {
+		cell, err := excelize.CoordinatesToCellName(col1+i, row1)
+		if err != nil {
+			return fmt.Errorf("AddTable: %w", err)
+		}
+		if err := e.f.SetCellStr(e.sheet, cell, name); err != nil {
+			return fmt.Errorf("AddTable: %w", err)
+		}
+	}
+	styleName := opt.StyleName
+	if styleName == "" {
+		styleName = "TableStyleLight16"
+	}
+	showHeaderRow, showRowStripes := !opt.HideHeaderRow, !opt.HideRowStripes
 	err = e.f.AddTable(
 		e.sheet,
 		&excelize.Table{
-			Range:     "A1:" + cellName,
-			Name:      table,
-			StyleName: "TableStyleLight16",
+			Range:             rangeRef,
+			Name:              table,
+			StyleName:         styleName,
+			ShowColumnStripes: opt.ShowColumnStripes,
+			ShowFirstColumn:   opt.ShowFirstColumn,
+			ShowHeaderRow:     &showHeaderRow,
+			ShowLastColumn:    opt.ShowLastColumn,
+			ShowRowStripes:    &showRowStripes,
 		},
 	)
 	if err != nil {
 		return fmt.Errorf("AddTable: %w", err)
 	}
+	if len(opt.Subtotals) > 0 {
+		// ヘッダ行を隠す場合は、1行目からデータ
+		dataRow := row1 + 1
+		if opt.HideHeaderRow {
+			dataRow = row1
+		}
+		if err := e.addSubtotalRow(col1, dataRow, row2, opt); err != nil {
+			return fmt.Errorf("AddTable: %w", err)
+		}
+	}
 	return nil
 }
 
+// checkTable checks the name and the options of a table at opt.Range, so
+// that AddTable and WriteDF do not change the sheet on an error.
+func (e *Excel) checkTable(table string, opt TableOptions) error {
+	col1, row1, col2, row2, err := rangeToCoordinates(opt.Range)
+	if err != nil {
+		return err
+	}
+	if len(opt.ColumnNames) > col2-col1+1 || len(opt.Subtotals) > col2-col1+1 {
+		return fmt.Errorf("too many columns for the range '%s'", opt.Range)
+	}
+	if opt.HideHeaderRow && len(opt.ColumnNames) > 0 {
+		return errors.New("ColumnNames cannot be used with HideHeaderRow")
+	}
+	for _, fn := range opt.Subtotals {
+		if _, ok := fn.subtotal(); !ok && fn != SubtotalNone {
+			return fmt.Errorf("invalid subtotal function: %v", fn)
+		}
+	}
+	if table != "" {
+		// 名前の規則は excelize と同じにするため、空のブックに追加して確かめる
+		f := excelize.NewFile()
+		defer f.Close()
+		if err := f.AddTable(f.GetSheetName(0),
+			&excelize.Table{Range: "A1:A2", Name: table}); err != nil {
+			return err
+		}
+		// Excel はテーブル名の大文字と小文字を区別しない
+		for _, sheet := range e.f.GetSheetList() {
+			tables, err := e.f.GetTables(sheet)
+			if err != nil {
+				// グラフシートなど、ワークシートでないシート
+				continue
+			}
+			for _, t := range tables {
+				if strings.EqualFold(t.Name, table) {
+					return fmt.Errorf("%w: %s", excelize.ErrExistsTableName, table)
+				}
+			}
+		}
+	}
+	// 結合したセルがあると、excelize がヘッダを "Column3" などで上書きする
+	merged, err := e.f.GetMergeCells(e.sheet)
+	if err != nil {
+		return err
+	}
+	for _, m := range merged {
+		mc1, mr1, mc2, mr2, err := rangeToCoordinates(
+			m.GetStartAxis() + ":" + m.GetEndAxis())
+		if err != nil {
+			return err
+		}
+		if mc1 <= col2 && col1 <= mc2 && mr1 <= row2 && row1 <= mr2 {
+			return fmt.Errorf("the range '%s' contains the merged cells '%s:%s'",
+				opt.Range, m.GetStartAxis(), m.GetEndAxis())
+		}
+	}
+	return nil
+}
+
+// addSubtotalRow writes the subtotal row just below the rows of a table.
+func (e *Excel) addSubtotalRow(col1, row1, row2 int, opt TableOptions) error {
+	label := opt.SubtotalLabel
+	if label == "" {
+		label = "合計"
+	}
+	for i, fn := range opt.Subtotals {
+		cell, err := excelize.CoordinatesToCellName(col1+i, row2+1)
+		if err != nil {
+			return err
+		}
+		n, ok := fn.subtotal()
+		switch {
+		case ok:
+			top, err := excelize.CoordinatesToCellName(col1+i, row1)
+			if err != nil {
+				return err
+			}
+			bottom, err := excelize.CoordinatesToCellName(col1+i, row2)
+			if err != nil {
+				return err
+			}
+			if err := e.f.SetCellFormula(e.sheet, cell,
+				fmt.Sprintf("SUBTOTAL(%d,%s:%s)", n, top, bottom)); err != nil {
+				return err
+			}
+		case fn == SubtotalNone:
+			if i == 0 {
+				if err := e.f.SetCellStr(e.sheet, cell, label); err != nil {
+					return err
+				}
+			}
+		default:
+			return fmt.Errorf("invalid subtotal function: %v", fn)
+		}
+		if e.cellStyleMap != nil {
+			// 集計行は太字にして、上に二重線を引く
+			if err := e.SetStyleForCell(cell, NewStyle(fontBold, bdT)); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// rangeToCoordinates converts a range such as "B3:F10" to the coordinates
+// of the top-left and bottom-right cells.
+func rangeToCoordinates(rangeRef string) (col1, row1, col2, row2 int,
+	err error) {
+	cell1, cell2, ok := strings.Cut(rangeRef, ":")
+	if !ok {
+		return 0, 0, 0, 0, fmt.Errorf("invalid range: %s", rangeRef)
+	}
+	if col1, row1, err = excelize.CellNameToCoordinates(cell1); err != nil {
+		return 0, 0, 0, 0, err
+	}
+	if col2, row2, err = excelize.CellNameToCoordinates(cell2); err != nil {
+		return 0, 0, 0, 0, err
+	}
+	// Normalize the range, such correct C1:B3 to B1:C3.
+	if col2 < col1 {
+		col1, col2 = col2, col1
+	}
+	if row2 < row1 {
+		row1, row2 = row2, row1
+	}
+	return col1, row1, col2, row2, nil
+}
+
 // CoordinatesToCellName is identical to the excelize module.
 func CoordinatesToCellName(col, row int, abs ...bool) (string, error) {
 	return excelize.CoordinatesToCellName(col, row, abs...)
@@ -2,9 +2,14 @@ package excel
 
 import (
 	"fmt"
+	"image"
+	"image/png"
+	"os"
 	"path/filepath"
+	"slices"
 	"sort"
 	"strconv"
+	"strings"
 	"testing"
 	"time"
 
@@ -296,6 +301,46 @@ func TestExcel_SaveAndClose(t *testing.T) {
 		t.Errorf("WriteDF: want no error, but: %v", err)
 	}
 
+	_ = e.H3("セルの書式設定: 配列形式の列のある表")
+	df = dataframe.New("B", "ID", "E", "送信元", "K", "宛先", "Q", "動作").
+		SetArray("送信元", "宛先").
+		AddGroup([]string{"A0001"}, []string{"host1", "host2"},
+			[]string{"ALL"}, []string{"PERMIT"}).
+		AddGroup([]string{"A0002"}, []string{"ALL"},
+			[]string{"web1", "web2"}, []string{"DENY"})
+	if err := e.WriteDF(df); err != nil {
+		t.Errorf("WriteDF: want no error, but: %v", err)
+	}
+
+	_ = e.H3("セルの書式設定: オプションを指定した表")
+	df = dataframe.New("B", "ID", "E", "名前", "I", "説明").
+		Add("1", "左詰め", "折り返して全体を表示する長い説明です。折り返して全体を表示する長い説明です。").
+		Add("2", "左詰め", "短い説明")
+	if err := e.WriteDFWithOptions(df, WriteDFOptions{
+		StartCol:        3,
+		EndCol:          30,
+		Align:           []HAlign{HAlignCenter, HAlignLeft, HAlignRight},
+		Wrap:            true,
+		HeaderFillColor: "#D9D9D9",
+	}); err != nil {
+		t.Errorf("WriteDFWithOptions: want no error, but: %v", err)
+	}
+	if e.Col != 30 {
+		t.Errorf("WriteDFWithOptions: want col 30, but %d", e.Col)
+	}
+	if err := e.WriteDFWithOptions(df, WriteDFOptions{AutoFit: true}); err != nil {
+		t.Errorf("WriteDFWithOptions: want no error, but: %v", err)
+	}
+	for _, opts := range []WriteDFOptions{
+		{HeaderFillColor: "123456"},
+		{EndCol: 5},
+		{Align: []HAlign{HAlign(99)}},
+	} {
+		if err := e.WriteDFWithOptions(df, opts); err == nil {
+			t.Errorf("WriteDFWithOptions: %+v: want error, but: %v", opts, err)
+		}
+	}
+
 	_ = e.H3("セルの書式設定: 入れ子構造の表")
 	cell1, _ := e.CR(2).LF().Cell()
 	_ = e.SetVal("タブ")
@@ -600,6 +645,204 @@ func TestExcel_SaveAndClose(t *testing.T) {
 	if err := e.AddTable("Table1"); err != nil {
 		t.Errorf("AddTable: want no error, but %v", err)
 	}
+
+	// シート「入力規則」
+	if err := e.NewSheet("入力規則", SheetTypeNormal); err != nil {
+		t.Errorf("NewSheet: want no error, but: %v", err)
+	}
+	df = dataframe.New("B", "ID", "E", "アドレス", "K", "状態", "Q", "動作").
+		SetAllowed("状態", "enable", "disable").
+		SetAllowed("動作", "accept", "deny").
+		Add("1", "192.168.0.1", "enable", "accept").
+		Add("2", "10.0.0.1", "disable", "deny")
+	if err := e.WriteDF(df); err != nil {
+		t.Errorf("WriteDF: want no error, but: %v", err)
+	}
+	if err := e.AddValidationCustom("E5:J6", ValidIPv4Formula("E5")); err != nil {
+		t.Errorf("AddValidationCustom: want no error, but: %v", err)
+	}
+	if err := e.HighlightWhen("B5:AG6", `$Q5="deny"`,
+		NewStyle().Fill("FF00FF").FontColor("#c00000").Bold()); err != nil {
+		t.Errorf("HighlightWhen: want no error, but: %v", err)
+	}
+	if err := e.HighlightWhen("K6:P6", "=K6<>K5", NewStyle(fillNote)); err != nil {
+		t.Errorf("HighlightWhen: want no error, but: %v", err)
+	}
+	dvs, err := e.f.GetDataValidations(e.sheet)
+	if err != nil {
+		t.Errorf("GetDataValidations: want no error, but: %v", err)
+	}
+	if len(dvs) != 3 {
+		t.Errorf("GetDataValidations: want 3, but %d", len(dvs))
+	}
+	for _, dv := range dvs {
+		if dv.Sqref == "K5:P6" && dv.Formula1 != `"enable,disable"` {
+			t.Errorf("WriteDF: want drop-down list, but %s", dv.Formula1)
+		}
+	}
+	cfs, err := e.f.GetConditionalFormats(e.sheet)
+	if err != nil {
+		t.Errorf("GetConditionalFormats: want no error, but: %v", err)
+	}
+	if len(cfs) != 2 {
+		t.Errorf("GetConditionalFormats: want 2, but %d", len(cfs))
+	}
+	if err := e.AddValidationList("B5:B6", nil); err == nil {
+		t.Errorf("AddValidationList: want error, but %v", err)
+	}
+	if err := e.AddValidationCustom("B5:B6", "="); err == nil {
+		t.Errorf("AddValidationCustom: want error, but %v", err)
+	}
+	if err := e.HighlightWhen("B5:B6", "", NewStyle()); err == nil {
+		t.Errorf("HighlightWhen: want error, but %v", err)
+	}
+
+	// シート「テーブル」
+	if err := e.NewSheet("テーブル"); err != nil {
+		t.Errorf("NewSheet: want no error, but: %v", err)
+	}
+	df = dataframe.New("B", "No", "C", "名前", "D", "点数").
+		Add("1", "大谷", "95").
+		Add("2", "鈴木", "88.5").
+		Add("0003", "野茂", "70")
+	if err := e.WriteDFWithOptions(df, WriteDFOptions{
+		AutoFit:         true,
+		Align:           []HAlign{HAlignCenter, HAlignGeneral, HAlignRight},
+		Wrap:            true,
+		HeaderFillColor: "D9D9D9",
+		TableName:       "Scores",
+		Table: TableOptions{
+			StyleName: "TableStyleMedium2",
+			Subtotals: []SubtotalFunc{SubtotalNone, SubtotalCount, SubtotalAverage},
+		},
+	}); err != nil {
+		t.Errorf("WriteDFWithOptions: want no error, but %v", err)
+	}
+	if got := e.LastDFRange(); got != "B2:D5" {
+		t.Errorf("LastDFRange: want B2:D5, but %s", got)
+	}
+	if got, _ := e.f.GetCellFormula(e.sheet, "D6"); got != "SUBTOTAL(101,D3:D5)" {
+		t.Errorf("Subtotals: want SUBTOTAL(101,D3:D5), but %s", got)
+	}
+	if got, _ := e.GetVal(2, 6); got != "合計" {
+		t.Errorf("Subtotals: want 合計, but %s", got)
+	}
+	if got, _ := e.f.GetCellType(e.sheet, "B5"); got == excelize.CellTypeNumber {
+		t.Errorf("WriteDFWithOptions: want string for 0003, but %v", got)
+	}
+	for cell, want := range map[string]cellStyle{
+		"B2": fillGray4,
+		"D2": fillGray4,
+		"B3": alignmentHorizontalCenter | alignmentWrapText,
+		"C4": alignmentWrapText,
+		"D5": alignmentHorizontalRight | alignmentWrapText,
+	} {
+		if got := e.cellStyleMap[cell]; got&want != want {
+			t.Errorf("WriteDFWithOptions: %s: want %v, but %v",
+				cell, want.Names(), got.Names())
+		}
+	}
+	e.CR().LF(2)
+	if err := e.SetRow(&[]any{"a", "b"}); err != nil {
+		t.Errorf("SetRow: want no error, but %v", err)
+	}
+	if err := e.LF().SetRow(&[]any{1, 2}); err != nil {
+		t.Errorf("SetRow: want no error, but %v", err)
+	}
+	if err := e.AddTable("Table2", TableOptions{
+		Range:       "A8:B9",
+		ColumnNames: []string{"列1", "列2"},
+		Subtotals:   []SubtotalFunc{SubtotalSum, SubtotalFunc(99)},
+	}); err == nil {
+		t.Errorf("AddTable: want error, but %v", err)
+	}
+	if err := e.AddTable("Table3", TableOptions{
+		Range:     "D8:E9",
+		Subtotals: []SubtotalFunc{SubtotalSum, SubtotalSum, SubtotalSum},
+	}); err == nil {
+		t.Errorf("AddTable: want error, but %v", err)
+	}
+	if err := e.AddTable("Table4", TableOptions{
+		Range:         "A8:B9",
+		ColumnNames:   []string{"列1", "列2"},
+		HideHeaderRow: true,
+	}); err == nil {
+		t.Errorf("AddTable: want error, but %v", err)
+	}
+	// エラーの場合はシートを変更しない
+	if tables, _ := e.f.GetTables(e.sheet); len(tables) != 1 {
+		t.Errorf("AddTable: want 1 table, but %v", tables)
+	}
+	if got, _ := e.GetVal(1, 8); got != "a" {
+		t.Errorf("AddTable: A8: want a, but %s", got)
+	}
+	if got, _ := e.f.GetCellFormula(e.sheet, "A10"); got != "" {
+		t.Errorf("AddTable: A10: want no formula, but %s", got)
+	}
+	// 名前が不正な場合や重複する場合も、ヘッダを書き換えない
+	for _, name := range []string{"bad name", "scores"} {
+		if err := e.AddTable(name, TableOptions{
+			Range: "A8:B9", ColumnNames: []string{"列1"}}); err == nil {
+			t.Errorf("AddTable %s: want error, but %v", name, err)
+		}
+		if got, _ := e.GetVal(1, 8); got != "a" {
+			t.Errorf("AddTable %s: A8: want a, but %s", name, got)
+		}
+	}
+	// WriteDF もテーブルのオプションを書き込む前に確認する
+	row := e.Row
+	for _, tt := range []struct {
+		name string
+		opts WriteDFOptions
+	}{
+		{"name", WriteDFOptions{TableName: "bad name"}},
+		{"duplicate", WriteDFOptions{TableName: "Scores"}},
+		{"totals", WriteDFOptions{TableName: "Other", Table: TableOptions{
+			Subtotals: []SubtotalFunc{SubtotalSum, SubtotalSum, SubtotalSum, SubtotalSum}}}},
+		{"function", WriteDFOptions{TableName: "Other", Table: TableOptions{
+			Subtotals: []SubtotalFunc{SubtotalFunc(99)}}}},
+		{"hide header", WriteDFOptions{TableName: "Other", Table: TableOptions{
+			HideHeaderRow: true, ColumnNames: []string{"列1"}}}},
+	} {
+		e.CR(1)
+		if err := e.WriteDFWithOptions(df, tt.opts); err == nil {
+			t.Errorf("WriteDFWithOptions %s: want error, but %v", tt.name, err)
+		}
+		if e.Row != row {
+			t.Errorf("WriteDFWithOptions %s: want row %d, but %d",
+				tt.name, row, e.Row)
+		}
+		if got, _ := e.GetVal(2, row+1); got != "" {
+			t.Errorf("WriteDFWithOptions %s: want no header, but %s",
+				tt.name, got)
+		}
+	}
+	// ヘッダ行を隠す場合は、1行目から集計する
+	if err := e.AddTable("Table5", TableOptions{
+		Range:         "A8:B9",
+		HideHeaderRow: true,
+		Subtotals:     []SubtotalFunc{SubtotalCount, SubtotalSum},
+	}); err != nil {
+		t.Errorf("AddTable: want no error, but %v", err)
+	}
+	if got, _ := e.f.GetCellFormula(e.sheet, "B10"); got != "SUBTOTAL(109,B8:B9)" {
+		t.Errorf("Subtotals: want SUBTOTAL(109,B8:B9), but %s", got)
+	}
+
+	// 結合したセルを含む TOMATO の表は、テーブルにできない
+	df = dataframe.New("B", "ID", "E", "名前").Add("1", "大谷")
+	e.CR().LF(2)
+	if err := e.WriteDF(df); err != nil {
+		t.Errorf("WriteDF: want no error, but %v", err)
+	}
+	err = e.AddTable("Table6", TableOptions{Range: e.LastDFRange()})
+	if err == nil || !strings.Contains(err.Error(), "merged") {
+		t.Errorf("AddTable: want error of the merged cells, but %v", err)
+	}
+	cell, _ := excelize.CoordinatesToCellName(2, e.Row-1)
+	if got, _ := e.f.GetCellValue(e.sheet, cell); got != "ID" {
+		t.Errorf("AddTable: %s: want ID, but %s", cell, got)
+	}
 }
 
 func Test_ColumnNumberToName(t *testing.T) {
@@ -626,6 +869,30 @@ func Test_ColumnNumberToName(t *testing.T) {
 	}
 }
 
+func Test_numericValue(t *testing.T) {
+	tests := []struct {
+		text string
+		want any
+	}{
+		{"95", 95.0},
+		{"88.5", 88.5},
+		{"-1", -1.0},
+		{"0003", "0003"},
+		{"1e3", "1e3"},
+		{"NaN", "NaN"},
+		{"Inf", "Inf"},
+		{"-Inf", "-Inf"},
+		{"+Inf", "+Inf"},
+		{"大谷", "大谷"},
+	}
+	for _, tt := range tests {
+		if got := numericValue(tt.text); got != tt.want {
+			t.Errorf("numericValue(%q): want %v (%T), but %v (%T)",
+				tt.text, tt.want, tt.want, got, got)
+		}
+	}
+}
+
 func Test_CordinatesToCellName(t *testing.T) {
 	tests := []struct {
 		name     string
@@ -704,6 +971,124 @@ func TestExcelMakeHeader(t *testing.T) {
 	}
 }
 
+func TestExcel_GetHeaderMarks(t *testing.T) {
+	e, err := OpenExcel(filepath.Join("./testdata", "Sample1.xlsx"))
+	if err != nil {
+		t.Fatalf("OpenExcel: want no error, but %v", err)
+	}
+	defer e.Close()
+	marks, err := e.GetHeaderMarks("ヘッダのサンプル")
+	if err != nil {
+		t.Fatalf("GetHeaderMarks: want no error, but %v", err)
+	}
+	want := []HeaderMark{
+		{Cell: "A1", Level: 1},
+		{Cell: "A5", Level: 2},
+		{Cell: "A7", Level: 3},
+		{Cell: "A9", Level: 3},
+		{Cell: "A11", Level: 2},
+		{Cell: "A13", Level: 2},
+		{Cell: "A15", Level: 3},
+	}
+	if len(marks) != len(want) {
+		t.Fatalf("GetHeaderMarks: want %d marks, but %v", len(want), marks)
+	}
+	for i, m := range marks {
+		if m.Cell != want[i].Cell || m.Level != want[i].Level {
+			t.Errorf("GetHeaderMarks: want %v, but %v", want[i], m)
+		}
+	}
+	if _, err := e.GetHeaderMarks("no such sheet"); err == nil {
+		t.Errorf("GetHeaderMarks: want error, but %v", err)
+	}
+}
+
+func Test_decodeStyle(t *testing.T) {
+	e, err := New("dummy.xlsx")
+	if err != nil {
+		t.Fatalf("New: want no error, but %v", err)
+	}
+	defer e.Close()
+	_ = e.NewSheet("foo")
+	style := NewStyle(fontSize12, fontRed, fillCaution, b1L, b3T, bdashR, bdB,
+		alignmentHorizontalCenter, alignmentWrapText).Bold()
+	if err := e.SetStyleForCell("B2", style); err != nil {
+		t.Fatalf("SetStyleForCell: want no error, but %v", err)
+	}
+	s, err := e.f.GetStyle(e.cellStyleIDs[e.cellStyleMap["B2"]])
+	if err != nil {
+		t.Fatalf("GetStyle: want no error, but %v", err)
+	}
+	got, ok := decodeStyle(s)
+	if want := style | fontFamilyYuGothic; got != want || !ok {
+		t.Errorf("decodeStyle: want %v, true, but %v, %v",
+			want.Names(), got.Names(), ok)
+	}
+
+	tests := []struct {
+		name  string
+		style *excelize.Style
+		want  cellStyle
+		ok    bool
+	}{
+		{"nil", nil, styleNormal, true},
+		{"palette color", &excelize.Style{
+			Fill: excelize.Fill{
+				Type: "pattern", Pattern: 1, Color: []string{"#ffff00"}},
+			Font: &excelize.Font{Color: "FF0563C1"},
+		}, fillYellow | fontHyperLink, true},
+		{"other fill color", &excelize.Style{
+			Fill: excelize.Fill{
+				Type: "pattern", Pattern: 1, Color: []string{"123456"}},
+		}, styleNormal, false},
+		{"dotted border", &excelize.Style{
+			Border: []excelize.Border{
+				{Type: "left", Style: 4}, {Type: "top", Style: 1}},
+		}, b1T, false},
+	}
+	for _, tt := range tests {
+		got, ok := decodeStyle(tt.style)
+		if got != tt.want || ok != tt.ok {
+			t.Errorf("%s: want %v, %v, but %v, %v",
+				tt.name, tt.want.Names(), tt.ok, got.Names(), ok)
+		}
+		names, ok := StyleNames(tt.style)
+		if !slices.Equal(names, tt.want.Names()) || ok != tt.ok {
+			t.Errorf("StyleNames: %s: want %v, %v, but %v, %v",
+				tt.name, tt.want.Names(), tt.ok, names, ok)
+		}
+	}
+}
+
+func TestCellStyle_WithFill(t *testing.T) {
+	tests := []struct {
+		fill, font string
+		want       cellStyle
+		ok         bool
+	}{
+		{"FFFF00", "#c00000", fillYellow | fontDeepRed | fontBold, true},
+		{"123456", "C00000", fontDeepRed | fontBold, false},
+		{"FFFF00", "123456", fillYellow | fontBold, false},
+	}
+	for _, tt := range tests {
+		got, err1 := NewStyle().Bold().WithFill(tt.fill)
+		got, err2 := got.WithFontColor(tt.font)
+		if ok := err1 == nil && err2 == nil; ok != tt.ok {
+			t.Errorf("WithFill(%s).WithFontColor(%s): want ok %v, but %v, %v",
+				tt.fill, tt.font, tt.ok, err1, err2)
+		}
+		if got != tt.want {
+			t.Errorf("WithFill(%s).WithFontColor(%s): want %v, but %v",
+				tt.fill, tt.font, tt.want.Names(), got.Names())
+		}
+		// 確認しない場合は、パレットにない色を無視する
+		if got := NewStyle().Bold().Fill(tt.fill).FontColor(tt.font); got != tt.want {
+			t.Errorf("Fill(%s).FontColor(%s): want %v, but %v",
+				tt.fill, tt.font, tt.want.Names(), got.Names())
+		}
+	}
+}
+
 func TestExcel_GetLastColumnNumberAndGetLastRowNumber(t *testing.T) {
 	tests := []struct {
 		name     string
@@ -850,3 +1235,788 @@ func TestExcel_HasVal(t *testing.T) {
 		}
 	}
 }
+
+func TestExcel_Lint(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "lint.xlsx")
+	e, err := New(filename)
+	if err != nil {
+		t.Fatalf("New: want no error, but %v", err)
+	}
+	for _, s := range []struct {
+		name  string
+		typ   SheetType
+		level int
+	}{
+		{"目次", SheetTypeTOC, 0},
+		{"本文", SheetTypeNormal, 2},
+		{"付録", SheetTypeNormal, 3},
+	} {
+		if err := e.NewSheet(s.name, s.typ); err != nil {
+			t.Fatalf("NewSheet: want no error, but %v", err)
+		}
+		switch s.level {
+		case 2:
+			err = e.H2("概要")
+		case 3:
+			err = e.H3("詳細")
+		}
+		if err != nil {
+			t.Fatalf("H%d: want no error, but %v", s.level, err)
+		}
+	}
+	// AutoFit で広げた表も右端を超えない
+	long := strings.Repeat("長い値", 10)
+	if err := e.WriteDFWithOptions(
+		dataframe.New("B", "名前", "E", "説明", "I", "備考").Add(long, long, long),
+		WriteDFOptions{AutoFit: true}); err != nil {
+		t.Fatalf("WriteDFWithOptions: want no error, but %v", err)
+	}
+	if err := e.SaveAndClose(); err != nil {
+		t.Fatalf("SaveAndClose: want no error, but %v", err)
+	}
+
+	e, err = OpenExcel(filename)
+	if err != nil {
+		t.Fatalf("OpenExcel: want no error, but %v", err)
+	}
+	defer e.Close()
+	landscape, left := "landscape", 1.0
+	if err := e.f.SetPageLayout("本文", &excelize.PageLayoutOptions{
+		Orientation: &landscape}); err != nil {
+		t.Fatalf("SetPageLayout: want no error, but %v", err)
+	}
+	if err := e.f.SetPageMargins("本文", &excelize.PageLayoutMarginsOptions{
+		Left: &left}); err != nil {
+		t.Fatalf("SetPageMargins: want no error, but %v", err)
+	}
+	if err := e.f.SetHeaderFooter("本文", &excelize.HeaderFooterOptions{
+		OddFooter: "&P"}); err != nil {
+		t.Fatalf("SetHeaderFooter: want no error, but %v", err)
+	}
+	if err := e.f.DeleteDefinedName(&excelize.DefinedName{
+		Name: printTitlesName, Scope: "付録"}); err != nil {
+		t.Fatalf("DeleteDefinedName: want no error, but %v", err)
+	}
+	if err := e.f.SetCellStr("本文", "AH10", "はみ出し"); err != nil {
+		t.Fatalf("SetCellStr: want no error, but %v", err)
+	}
+	marks, err := e.GetHeaderMarks("本文")
+	if err != nil || len(marks) != 2 {
+		t.Fatalf("GetHeaderMarks: want 2 marks, but %v, %v", marks, err)
+	}
+	if err := e.f.SetCellStr("本文", marks[1].Cell, "2. 変更"); err != nil {
+		t.Fatalf("SetCellStr: want no error, but %v", err)
+	}
+
+	type result struct {
+		location, rule string
+		fixable        bool
+	}
+	lint := func() []result {
+		t.Helper()
+		violations, err := e.Lint()
+		if err != nil {
+			t.Fatalf("Lint: want no error, but %v", err)
+		}
+		var got []result
+		for _, v := range violations {
+			got = append(got, result{v.Location(), v.Rule, v.Fixable()})
+		}
+		return got
+	}
+	unfixable := []result{
+		{"本文!AH10", ruleRightEdge, false},
+		{"付録!" + marks[1].Cell, ruleHeading, false},
+		{"目次!C6", ruleTOC, false},
+	}
+	want := append([]result{
+		{"本文", ruleOrientation, true},
+		{"本文", ruleMargins, true},
+		{"本文", ruleFooter, true},
+		{"付録", rulePrintTitles, true},
+	}, unfixable...)
+	got := lint()
+	sortResults := func(r []result) {
+		sort.Slice(r, func(i, j int) bool {
+			return r[i].location+r[i].rule < r[j].location+r[j].rule
+		})
+	}
+	sortResults(got)
+	sortResults(want)
+	if fmt.Sprint(got) != fmt.Sprint(want) {
+		t.Errorf("Lint: want %v, but %v", want, got)
+	}
+
+	violations, _ := e.Lint()
+	fixed, err := e.Fix(violations)
+	if err != nil {
+		t.Fatalf("Fix: want no error, but %v", err)
+	}
+	if fixed != 4 {
+		t.Errorf("Fix: want 4 fixed, but %d", fixed)
+	}
+	got = lint()
+	sortResults(got)
+	sortResults(unfixable)
+	if fmt.Sprint(got) != fmt.Sprint(unfixable) {
+		t.Errorf("Lint after Fix: want %v, but %v", unfixable, got)
+	}
+}
+
+func TestNewFromTemplate(t *testing.T) {
+	dir := t.TempDir()
+	tmpl := filepath.Join(dir, "template.xlsx")
+	f := excelize.NewFile()
+	if err := f.SetSheetName("Sheet1", "表紙"); err != nil {
+		t.Fatalf("SetSheetName: want no error, but %v", err)
+	}
+	brand, err := f.NewStyle(&excelize.Style{Fill: excelize.Fill{
+		Type: "pattern", Pattern: 1, Color: []string{"C00000"}}})
+	if err != nil {
+		t.Fatalf("NewStyle: want no error, but %v", err)
+	}
+	for _, v := range []struct{ cell, value string }{
+		{"A1", "{{.Title}}"},
+		{"A2", "作成者: {{.Author}}"},
+		{"A3", "{{ 置換しない"},
+		{"A5", "{{ref:tbl-x}} ({{.Title}})"},
+		{"A6", "{{ref:tbl-x}}"},
+	} {
+		if err := f.SetCellStr("表紙", v.cell, v.value); err != nil {
+			t.Fatalf("SetCellStr: want no error, but %v", err)
+		}
+	}
+	if err := f.SetCellFormula("表紙", "A4", `"{{.Title}}"`); err != nil {
+		t.Fatalf("SetCellFormula: want no error, but %v", err)
+	}
+	if err := f.SetCellStyle("表紙", "A1", "A1", brand); err != nil {
+		t.Fatalf("SetCellStyle: want no error, but %v", err)
+	}
+	if err := f.SetDefinedName(&excelize.DefinedName{
+		Name: "会社名", RefersTo: "'表紙'!$A$2"}); err != nil {
+		t.Fatalf("SetDefinedName: want no error, but %v", err)
+	}
+	if err := f.SaveAs(tmpl); err != nil {
+		t.Fatalf("SaveAs: want no error, but %v", err)
+	}
+	f.Close()
+
+	if _, err := NewFromTemplate(tmpl, filepath.Join(dir, "out.xlsx"),
+		map[string]string{"Title": "設計書"}); err == nil {
+		t.Errorf("NewFromTemplate: want missing key error, but %v", err)
+	}
+	if _, err := NewFromTemplate(filepath.Join(dir, "none.xlsx"),
+		filepath.Join(dir, "out.xlsx"), nil); err == nil {
+		t.Errorf("NewFromTemplate: want error, but %v", err)
+	}
+
+	out := filepath.Join(dir, "out.xlsx")
+	e, err := NewFromTemplate(tmpl, out,
+		map[string]string{"Title": "設計書", "Author": "山田"})
+	if err != nil {
+		t.Fatalf("NewFromTemplate: want no error, but %v", err)
+	}
+	if err := e.NewSheet("本文", SheetTypeNormal); err != nil {
+		t.Fatalf("NewSheet: want no error, but %v", err)
+	}
+	if err := e.H2("概要"); err != nil {
+		t.Fatalf("H2: want no error, but %v", err)
+	}
+	if err := e.SaveAndClose(); err != nil {
+		t.Fatalf("SaveAndClose: want no error, but %v", err)
+	}
+
+	e, err = OpenExcel(out)
+	if err != nil {
+		t.Fatalf("OpenExcel: want no error, but %v", err)
+	}
+	defer e.Close()
+	if got := e.f.GetSheetList(); fmt.Sprint(got) != "[表紙 本文]" {
+		t.Errorf("GetSheetList: want [表紙 本文], but %v", got)
+	}
+	for _, tt := range []struct{ cell, want string }{
+		{"A1", "設計書"},
+		{"A2", "作成者: 山田"},
+		{"A3", "{{ 置換しない"},            // テンプレートでない "{{" はそのまま
+		{"A5", "{{ref:tbl-x}} (設計書)"}, // 相互参照はそのまま
+		{"A6", "{{ref:tbl-x}}"},
+	} {
+		if got, _ := e.f.GetCellValue("表紙", tt.cell); got != tt.want {
+			t.Errorf("GetCellValue %s: want %s, but %s", tt.cell, tt.want, got)
+		}
+	}
+	if got, _ := e.f.GetCellFormula("表紙", "A4"); got != `"{{.Title}}"` {
+		t.Errorf("GetCellFormula: want the formula kept, but %s", got)
+	}
+	styleID, _ := e.f.GetCellStyle("表紙", "A1")
+	if style, err := e.f.GetStyle(styleID); err != nil ||
+		len(style.Fill.Color) == 0 || style.Fill.Color[0] != "C00000" {
+		t.Errorf("GetStyle: want the fill of the template, but %v, %v",
+			style, err)
+	}
+	var found bool
+	for _, dn := range e.f.GetDefinedName() {
+		found = found || dn.Name == "会社名"
+	}
+	if !found {
+		t.Errorf("GetDefinedName: want 会社名, but %v", e.f.GetDefinedName())
+	}
+	if marks, err := e.GetHeaderMarks("本文"); err != nil || len(marks) != 2 {
+		t.Errorf("GetHeaderMarks: want 2 marks, but %v, %v", marks, err)
+	}
+}
+
+func TestExcel_ExecuteTemplate(t *testing.T) {
+	type policy struct {
+		No           int
+		Name, Action string
+	}
+	data := struct {
+		Title        string
+		Policies     []policy
+		Notes, Empty []string
+		Tags         []string
+	}{
+		Title: "報告書",
+		Policies: []policy{
+			{1, "web", "accept"}, {2, "ssh", "accept"}, {3, "any", "deny"}},
+		Notes: []string{"n1", "n2"},
+		Tags:  []string{"a", "b"},
+	}
+
+	newTemplate := func(t *testing.T, cells map[string]string,
+		merges ...string) *Excel {
+		t.Helper()
+		e, err := New(filepath.Join(t.TempDir(), "report.xlsx"))
+		if err != nil {
+			t.Fatalf("New: want no error, but %v", err)
+		}
+		t.Cleanup(func() { e.Close() })
+		f := e.GetFile()
+		for cell, value := range cells {
+			if err := f.SetCellStr(defaultSheet, cell, value); err != nil {
+				t.Fatalf("SetCellStr: want no error, but %v", err)
+			}
+		}
+		for _, m := range merges {
+			cell1, cell2, _ := strings.Cut(m, ":")
+			if err := f.MergeCell(defaultSheet, cell1, cell2); err != nil {
+				t.Fatalf("MergeCell: want no error, but %v", err)
+			}
+		}
+		return e
+	}
+
+	e := newTemplate(t, map[string]string{
+		"A1":  "{{.Title}}",
+		"C1":  "{{len .Notes}}",
+		"E1":  "{{ テンプレートでない括弧",
+		"A3":  "No",
+		"A4":  "{{range .Policies}}{{.No}}",
+		"B4":  "{{.Name}}",
+		"D4":  "{{.Action}} ({{$.Title}}){{end}}",
+		"A6":  "合計: {{len .Policies}}",
+		"A7":  "注記",
+		"A10": "{{range .Notes}}{{.}}",
+		"A11": "---{{end}}",
+		"A13": "{{range .Empty}}{{.}}",
+		"B13": "{{end}}",
+		"A15": "{{range .Tags}}{{.}},{{end}}",
+	}, "B4:C4", "A7:B8", "B10:B11")
+	f := e.GetFile()
+	style, err := f.NewStyle(&excelize.Style{Border: []excelize.Border{
+		{Type: "bottom", Color: "000000", Style: 1}}})
+	if err != nil {
+		t.Fatalf("NewStyle: want no error, but %v", err)
+	}
+	if err := f.SetCellStyle(defaultSheet, "A4", "D4", style); err != nil {
+		t.Fatalf("SetCellStyle: want no error, but %v", err)
+	}
+	if err := e.ExecuteTemplate(data); err != nil {
+		t.Fatalf("ExecuteTemplate: want no error, but %v", err)
+	}
+
+	for _, tt := range []struct{ cell, want string }{
+		{"A1", "報告書"},
+		{"C1", "2"},
+		{"E1", "{{ テンプレートでない括弧"},
+		{"A3", "No"},
+		{"A4", "1"}, {"B4", "web"}, {"D4", "accept (報告書)"},
+		{"A5", "2"}, {"B5", "ssh"},
+		{"A6", "3"}, {"D6", "deny (報告書)"},
+		{"A8", "合計: 3"},
+		{"A9", "注記"},
+		{"A12", "n1"}, {"A13", "---"}, {"A14", "n2"}, {"A15", "---"},
+		{"A17", ""},
+		{"A18", "a,b,"},
+	} {
+		if got, _ := f.GetCellValue(defaultSheet, tt.cell); got != tt.want {
+			t.Errorf("GetCellValue %s: want %q, but %q", tt.cell, tt.want, got)
+		}
+	}
+	if got, _ := f.GetCellStyle(defaultSheet, "D6"); got != style {
+		t.Errorf("GetCellStyle D6: want %d, but %d", style, got)
+	}
+	// 数値は数値のまま (excelize は数値のセルの型を省略する)
+	for cell, want := range map[string]excelize.CellType{
+		"C1": excelize.CellTypeUnset,
+		"A5": excelize.CellTypeUnset,
+		"B5": excelize.CellTypeSharedString,
+		"E1": excelize.CellTypeSharedString,
+	} {
+		if got, _ := f.GetCellType(defaultSheet, cell); got != want {
+			t.Errorf("GetCellType %s: want %v, but %v", cell, want, got)
+		}
+	}
+	merged, err := f.GetMergeCells(defaultSheet)
+	if err != nil {
+		t.Fatalf("GetMergeCells: want no error, but %v", err)
+	}
+	var got []string
+	for _, m := range merged {
+		got = append(got, m.GetStartAxis()+":"+m.GetEndAxis())
+	}
+	sort.Strings(got)
+	want := []string{"A9:B10", "B12:B13", "B14:B15", "B4:C4", "B5:C5",
+		"B6:C6"}
+	if fmt.Sprint(got) != fmt.Sprint(want) {
+		t.Errorf("GetMergeCells: want %v, but %v", want, got)
+	}
+
+	// range の対象は要素の数によらず一度だけ評価する
+	calls := 0
+	items := struct{ Items func() []int }{func() []int {
+		calls++
+		s := make([]int, 1000)
+		for i := range s {
+			s[i] = i + 1
+		}
+		return s
+	}}
+	e = newTemplate(t, map[string]string{
+		"A1": "{{range call .Items}}{{.}}", "B1": "{{.}}{{end}}"})
+	if err := e.ExecuteTemplate(items); err != nil {
+		t.Fatalf("ExecuteTemplate: want no error, but %v", err)
+	}
+	if calls != 1 {
+		t.Errorf("ExecuteTemplate: want 1 call, but %d", calls)
+	}
+	if got, _ := e.GetFile().GetCellValue(defaultSheet, "B1000"); got != "1000" {
+		t.Errorf("GetCellValue B1000: want 1000, but %q", got)
+	}
+
+	// エラー
+	for _, tt := range []struct {
+		name  string
+		cells map[string]string
+	}{
+		{"no end", map[string]string{"A1": "{{range .Policies}}{{.No}}"}},
+		{"not slice", map[string]string{
+			"A1": "{{range .Title}}{{.}}", "B1": "{{end}}"}},
+		{"nested", map[string]string{
+			"A1": "{{range .Policies}}", "A2": "{{range .Notes}}",
+			"A3": "{{end}}"}},
+		{"missing", map[string]string{"A1": "{{.Missing}}"}},
+	} {
+		e := newTemplate(t, tt.cells)
+		if err := e.ExecuteTemplate(data); err == nil {
+			t.Errorf("ExecuteTemplate %s: want error, but %v", tt.name, err)
+		}
+	}
+}
+
+func TestExcel_InsertImage(t *testing.T) {
+	dir := t.TempDir()
+	newPNG := func(name string, width, height int) string {
+		t.Helper()
+		path := filepath.Join(dir, name)
+		file, err := os.Create(path)
+		if err != nil {
+			t.Fatalf("Create: want no error, but %v", err)
+		}
+		defer file.Close()
+		img := image.NewRGBA(image.Rect(0, 0, width, height))
+		if err := png.Encode(file, img); err != nil {
+			t.Fatalf("Encode: want no error, but %v", err)
+		}
+		return path
+	}
+	wide := newPNG("wide.png", 1408, 180)
+	small := newPNG("small.png", 40, 30)
+
+	filename := filepath.Join(dir, "image.xlsx")
+	e, err := New(filename)
+	if err != nil {
+		t.Fatalf("New: want no error, but %v", err)
+	}
+	if err := e.NewSheet(tocSheet, SheetTypeTOC); err != nil {
+		t.Fatalf("NewSheet: want no error, but %v", err)
+	}
+	if err := e.NewSheet("構成", SheetTypeNormal); err != nil {
+		t.Fatalf("NewSheet: want no error, but %v", err)
+	}
+
+	// B5 から AG まで 32 列 (22 ピクセル) = 704 ピクセルに縮小する
+	e.Col, e.Row = 2, 5
+	if err := e.InsertImage(wide, ImageOptions{
+		Caption: "全体構成", Border: true}); err != nil {
+		t.Fatalf("InsertImage: want no error, but %v", err)
+	}
+	// 90 ピクセル = 16 ピクセルの行 × 6 行
+	if e.Row != 12 {
+		t.Errorf("InsertImage: want row 12, but %d", e.Row)
+	}
+	if err := e.InsertImage(small, ImageOptions{Caption: "詳細"}); err != nil {
+		t.Fatalf("InsertImage: want no error, but %v", err)
+	}
+	if e.Row != 15 {
+		t.Errorf("InsertImage: want row 15, but %d", e.Row)
+	}
+	if err := e.InsertImage(small); err != nil {
+		t.Fatalf("InsertImage: want no error, but %v", err)
+	}
+	if err := e.InsertImage(filepath.Join(dir, "none.png")); err == nil {
+		t.Errorf("InsertImage: want error, but %v", err)
+	}
+	if err := e.InsertImage(filename); err == nil {
+		t.Errorf("InsertImage: want error for not an image, but %v", err)
+	}
+	if err := e.SaveAndClose(); err != nil {
+		t.Fatalf("SaveAndClose: want no error, but %v", err)
+	}
+
+	e, err = OpenExcel(filename)
+	if err != nil {
+		t.Fatalf("OpenExcel: want no error, but %v", err)
+	}
+	defer e.Close()
+	pics, err := e.f.GetPictures("構成", "B5")
+	if err != nil || len(pics) != 1 {
+		t.Fatalf("GetPictures: want 1 picture, but %v, %v", pics, err)
+	}
+	if scale := pics[0].Format.ScaleX; scale != 0.5 {
+		t.Errorf("GetPictures: want scale 0.5, but %v", scale)
+	}
+	for _, tt := range []struct{ sheet, cell, want string }{
+		{"構成", "B11", "図 1-1 全体構成"},
+		{"構成", "B14", "図 1-2 詳細"},
+		{tocSheet, "B7", "図目次"},
+		{tocSheet, "C8", "図 1-1 全体構成"},
+		{tocSheet, "C9", "図 1-2 詳細"},
+	} {
+		if got, _ := e.f.GetCellValue(tt.sheet, tt.cell); got != tt.want {
+			t.Errorf("GetCellValue %s!%s: want %q, but %q",
+				tt.sheet, tt.cell, tt.want, got)
+		}
+	}
+	if ok, link, _ := e.f.GetCellHyperLink(tocSheet, "C9"); !ok ||
+		link != "'構成'!$B$14" {
+		t.Errorf("GetCellHyperLink: want '構成'!$B$14, but %v %s", ok, link)
+	}
+	styleID, _ := e.f.GetCellStyle("構成", "B5")
+	if style, _ := e.f.GetStyle(styleID); style == nil || len(style.Border) == 0 {
+		t.Errorf("GetStyle B5: want border, but %v", style)
+	}
+}
+
+func TestExcel_Caption(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "caption.xlsx")
+	e, err := New(filename)
+	if err != nil {
+		t.Fatalf("New: want no error, but %v", err)
+	}
+	if err := e.NewSheet(tocSheet, SheetTypeTOC); err != nil {
+		t.Fatalf("NewSheet: want no error, but %v", err)
+	}
+	if err := e.NewSheet("概要", SheetTypeNormal); err != nil {
+		t.Fatalf("NewSheet: want no error, but %v", err)
+	}
+	tests := []struct {
+		kind CaptionKind
+		text string
+		id   string
+	}{
+		{CaptionTable, "機器一覧", "tbl-devices"},
+		{CaptionFigure, "全体構成", ""},
+		{CaptionTable, "アドレス一覧", ""},
+	}
+	for _, tt := range tests {
+		if err := e.CR(2).Caption(tt.kind, tt.text, tt.id); err != nil {
+			t.Fatalf("Caption: want no error, but %v", err)
+		}
+	}
+	if e.Row != 6 {
+		t.Errorf("Caption: want row 6, but %d", e.Row)
+	}
+	if err := e.Caption(CaptionTable, "重複", "tbl-devices"); err == nil {
+		t.Errorf("Caption: want error for duplicate id, but %v", err)
+	}
+	if err := e.Caption(CaptionKind(9), "不明"); err == nil {
+		t.Errorf("Caption: want error for unknown kind, but %v", err)
+	}
+	if err := e.NewSheet("設定", SheetTypeNormal); err != nil {
+		t.Fatalf("NewSheet: want no error, but %v", err)
+	}
+	if err := e.CR(2).SetVal(
+		"{{ref:tbl-policies}} は {{ref:tbl-devices}} の機器に適用する。"); err != nil {
+		t.Fatalf("SetVal: want no error, but %v", err)
+	}
+	if err := e.LF().Caption(CaptionTable, "ポリシー一覧",
+		"tbl-policies"); err != nil {
+		t.Fatalf("Caption: want no error, but %v", err)
+	}
+	if err := e.SaveAndClose(); err != nil {
+		t.Fatalf("SaveAndClose: want no error, but %v", err)
+	}
+
+	e, err = OpenExcel(filename)
+	if err != nil {
+		t.Fatalf("OpenExcel: want no error, but %v", err)
+	}
+	defer e.Close()
+	for _, tt := range []struct{ sheet, cell, want string }{
+		{"概要", "B3", "表 1-1 機器一覧"},
+		{"概要", "B4", "図 1-1 全体構成"},
+		{"概要", "B5", "表 1-2 アドレス一覧"},
+		{"設定", "B3", "表 2-1 は 表 1-1 の機器に適用する。"},
+		{"設定", "B4", "表 2-1 ポリシー一覧"},
+		{tocSheet, "B9", "図目次"},
+		{tocSheet, "C10", "図 1-1 全体構成"},
+		{tocSheet, "B12", "表目次"},
+		{tocSheet, "C15", "表 2-1 ポリシー一覧"},
+	} {
+		if got, _ := e.f.GetCellValue(tt.sheet, tt.cell); got != tt.want {
+			t.Errorf("GetCellValue %s!%s: want %q, but %q",
+				tt.sheet, tt.cell, tt.want, got)
+		}
+	}
+	if ok, link, _ := e.f.GetCellHyperLink("設定", "B3"); !ok ||
+		link != "'設定'!$B$4" {
+		t.Errorf("GetCellHyperLink: want '設定'!$B$4, but %v %s", ok, link)
+	}
+
+	// 存在しない参照
+	filename = filepath.Join(t.TempDir(), "unknown.xlsx")
+	e2, err := New(filename)
+	if err != nil {
+		t.Fatalf("New: want no error, but %v", err)
+	}
+	if err := e2.NewSheet("概要", SheetTypeNormal); err != nil {
+		t.Fatalf("NewSheet: want no error, but %v", err)
+	}
+	if err := e2.Caption(CaptionTable, "一覧", "tbl-list"); err != nil {
+		t.Fatalf("Caption: want no error, but %v", err)
+	}
+	if err := e2.SetVal("{{ref:missing}} を参照"); err != nil {
+		t.Fatalf("SetVal: want no error, but %v", err)
+	}
+	if err := e2.SaveAndClose(); err == nil ||
+		!strings.Contains(err.Error(), "missing") {
+		t.Errorf("SaveAndClose: want unknown reference error, but %v", err)
+	}
+
+	// Caption を使わない場合、参照はそのまま残す
+	filename = filepath.Join(t.TempDir(), "nocaption.xlsx")
+	e2, err = New(filename)
+	if err != nil {
+		t.Fatalf("New: want no error, but %v", err)
+	}
+	if err := e2.NewSheet("概要", SheetTypeNormal); err != nil {
+		t.Fatalf("NewSheet: want no error, but %v", err)
+	}
+	cell, err := e2.Cell()
+	if err != nil {
+		t.Fatalf("Cell: want no error, but %v", err)
+	}
+	if err := e2.SetVal("{{ref:missing}} を参照"); err != nil {
+		t.Fatalf("SetVal: want no error, but %v", err)
+	}
+	if err := e2.SaveAndClose(); err != nil {
+		t.Fatalf("SaveAndClose: want no error, but %v", err)
+	}
+	e2, err = OpenExcel(filename)
+	if err != nil {
+		t.Fatalf("OpenExcel: want no error, but %v", err)
+	}
+	if err := e2.SaveAndClose(); err != nil {
+		t.Fatalf("SaveAndClose: want no error, but %v", err)
+	}
+	e2, err = OpenExcel(filename)
+	if err != nil {
+		t.Fatalf("OpenExcel: want no error, but %v", err)
+	}
+	defer e2.Close()
+	if got, _ := e2.f.GetCellValue("概要", cell); got != "{{ref:missing}} を参照" {
+		t.Errorf("GetCellValue: want the reference kept, but %q", got)
+	}
+}
+
+func TestExcel_SetProgress(t *testing.T) {
+	type call struct {
+		step, total int
+		label       string
+	}
+	var calls []call
+	e, err := New(filepath.Join(t.TempDir(), "progress.xlsx"))
+	if err != nil {
+		t.Fatalf("New: want no error, but %v", err)
+	}
+	e.SetProgress(func(step, total int, label string) {
+		calls = append(calls, call{step, total, label})
+	})
+	if err := e.NewSheet(tocSheet, SheetTypeTOC); err != nil {
+		t.Fatalf("NewSheet: want no error, but %v", err)
+	}
+	if err := e.NewSheet("一覧", SheetTypeNormal); err != nil {
+		t.Fatalf("NewSheet: want no error, but %v", err)
+	}
+	df := dataframe.New("B", "名前", "E", "アドレス").
+		Add("a", "10.0.0.1").Add("b", "10.0.0.2").Add("c", "10.0.0.3")
+	if err := e.WriteDF(df); err != nil {
+		t.Fatalf("WriteDF: want no error, but %v", err)
+	}
+	if err := e.SaveAndClose(); err != nil {
+		t.Fatalf("SaveAndClose: want no error, but %v", err)
+	}
+	for _, want := range []call{
+		{1, 3, "WriteDF"},
+		{3, 3, "WriteDF"},
+		{2, 2, "MakeTOC: 一覧"},
+	} {
+		if !slices.Contains(calls, want) {
+			t.Errorf("SetProgress: want %+v, but %+v", want, calls)
+		}
+	}
+	if last := calls[len(calls)-1]; last.total != 0 ||
+		!strings.HasPrefix(last.label, "SaveAndClose") {
+		t.Errorf("SetProgress: want SaveAndClose last, but %+v", last)
+	}
+}
+
+func TestExcel_WriteDFWithOptions(t *testing.T) {
+	e, err := New("dummy.xlsx")
+	if err != nil {
+		t.Fatalf("New: want no error, but %v", err)
+	}
+	defer e.Close()
+	_ = e.NewSheet("foo")
+
+	// 値とスタイルを確認する
+	check := func(name string, cells map[string]string, styles map[string]cellStyle) {
+		t.Helper()
+		for cell, want := range cells {
+			if got, _ := e.f.GetCellValue("foo", cell); got != want {
+				t.Errorf("%s: %s: want %q, but %q", name, cell, want, got)
+			}
+		}
+		for cell, want := range styles {
+			if got := e.cellStyleMap[cell]; got&want != want {
+				t.Errorf("%s: %s: want %v, but %v",
+					name, cell, want.Names(), got.Names())
+			}
+		}
+	}
+
+	df := dataframe.New("B", "ID", "E", "名前", "I", "説明").
+		Add("1", "左詰め", "説明").
+		Add("2", "左詰め", "短い説明")
+	e.Row = 1
+	if err := e.WriteDFWithOptions(df, WriteDFOptions{
+		StartCol:        3,
+		EndCol:          30,
+		Align:           []HAlign{HAlignCenter, HAlignLeft, HAlignRight},
+		Wrap:            true,
+		HeaderFillColor: "D9D9D9",
+	}); err != nil {
+		t.Fatalf("WriteDFWithOptions: want no error, but: %v", err)
+	}
+	check("StartCol", map[string]string{"C2": "ID", "F2": "名前", "J2": "説明"},
+		map[string]cellStyle{
+			"C2":  fillGray4,
+			"AD2": fillGray4,
+			"C3":  alignmentHorizontalCenter | alignmentWrapText,
+			"F4":  alignmentHorizontalLeft | alignmentWrapText,
+			"AD4": alignmentHorizontalRight | alignmentWrapText,
+		})
+	if e.Col != 30 {
+		t.Errorf("StartCol: want col 30, but %d", e.Col)
+	}
+	if got := e.cellStyleMap["I3"]; got&alignmentHorizontalRight != 0 {
+		t.Errorf("StartCol: I3: want no right alignment, but %v", got.Names())
+	}
+
+	// StartCol が 0 の場合は、ヘッダの列のまま
+	e.Row = 10
+	df2 := dataframe.New("D", "ID", "H", "名前").Add("1", "a")
+	if err := e.WriteDFWithOptions(df2, WriteDFOptions{}); err != nil {
+		t.Fatalf("WriteDFWithOptions: want no error, but: %v", err)
+	}
+	check("no shift", map[string]string{"D11": "ID", "H11": "名前", "D12": "1"}, nil)
+
+	// 列の幅に合わせて配置する
+	e.Row = 20
+	if err := e.WriteDFWithOptions(df, WriteDFOptions{AutoFit: true}); err != nil {
+		t.Fatalf("WriteDFWithOptions: want no error, but: %v", err)
+	}
+	check("AutoFit", map[string]string{"B21": "ID", "D21": "名前", "G21": "説明"},
+		nil)
+	if e.Col != maxRightCellNumber {
+		t.Errorf("AutoFit: want col %d, but %d", maxRightCellNumber, e.Col)
+	}
+
+	// EndCol を超えないように詰める
+	long := strings.Repeat("あ", 30)
+	df3 := dataframe.New("B", "A", "C", "B", "D", "C").Add(long, long, long)
+	e.Row = 30
+	if err := e.WriteDFWithOptions(df3, WriteDFOptions{AutoFit: true}); err != nil {
+		t.Fatalf("WriteDFWithOptions: want no error, but: %v", err)
+	}
+	check("AutoFit overflow", map[string]string{
+		"B31": "A", "Y31": "B", "AG31": "C", "AG32": long}, nil)
+	if last, _ := e.f.GetCellValue("foo", "AH31"); last != "" {
+		t.Errorf("AutoFit overflow: AH31: want blank, but %q", last)
+	}
+	if err := e.WriteDFWithOptions(df3,
+		WriteDFOptions{AutoFit: true, StartCol: 32}); err == nil {
+		t.Errorf("AutoFit: want error, but %v", err)
+	}
+}
+
+func TestExcel_MarkHeader(t *testing.T) {
+	e, err := New("dummy.xlsx")
+	if err != nil {
+		t.Fatalf("New: want no error, but %v", err)
+	}
+	defer e.Close()
+	_ = e.NewSheet("foo")
+
+	// ヘッダの印は付け直す
+	_ = e.CR(2).LF().SetVal("概要")
+	for _, level := range []int{1, 2} {
+		if err := e.MarkHeader(level); err != nil {
+			t.Fatalf("MarkHeader(%d): want no error, but %v", level, err)
+		}
+	}
+	marks, _ := e.GetHeaderMarks("foo")
+	want := []HeaderMark{{Cell: "B2", Level: 2, Value: "概要"}}
+	if !slices.Equal(marks, want) {
+		t.Errorf("MarkHeader: want %v, but %v", want, marks)
+	}
+
+	// ヘッダの印でないコメントは削除しない
+	if err := e.f.AddComment("foo", excelize.Comment{
+		Cell: "B4", Author: "user", Text: "確認済み"}); err != nil {
+		t.Fatalf("AddComment: want no error, but %v", err)
+	}
+	if err := e.CR(2).LF(2).MarkHeader(2); err == nil {
+		t.Errorf("MarkHeader: want error, but %v", err)
+	}
+	comments, _ := e.f.GetComments("foo")
+	if len(comments) != 2 || comments[1].Cell != "B4" ||
+		comments[1].Text != "確認済み" {
+		t.Errorf("MarkHeader: want the comment of the user, but %+v", comments)
+	}
+	if got := e.cellStyleMap["B4"]; got&fontBold != 0 {
+		t.Errorf("MarkHeader: B4: want not bold, but %v", got.Names())
+	}
+}
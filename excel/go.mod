@@ -5,6 +5,7 @@ go 1.26
 require (
 	github.com/mattn/go-runewidth v0.0.21
 	github.com/xuri/excelize/v2 v2.10.1
+	gopkg.in/yaml.v3 v3.0.1
 )
 
 require (
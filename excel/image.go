@@ -0,0 +1,136 @@
+package excel
+
+import (
+	"fmt"
+	"image"
+	_ "image/gif"
+	_ "image/jpeg"
+	_ "image/png"
+	"math"
+	"os"
+
+	"github.com/xuri/excelize/v2"
+)
+
+// ImageOptions specifies how InsertImage places an image.
+type ImageOptions struct {
+	// Caption is written below the image by Caption, numbered such as
+	// "図 1-4". No caption is written if empty.
+	Caption string
+	// ID names the caption for the cross-references such as {{ref:ID}}.
+	ID string
+	// Border draws a thin border around the cells covered by the image.
+	Border bool
+}
+
+// InsertImage inserts a PNG, JPEG or GIF image at the cursor (Col, Row).
+// An image wider than the span from the cursor to maxRightCell is scaled
+// down to fit it. The cursor moves below the image, and below the caption
+// if any.
+//
+// When the workbook has the TOC sheet, SaveAndClose writes the list of
+// the figures below the table of contents.
+//
+// Example:
+//
+//	err := e.InsertImage("topology.png", ImageOptions{Caption: "構成図"})
+func (e *Excel) InsertImage(path string, opts ...ImageOptions) error {
+	var opt ImageOptions
+	if len(opts) > 0 {
+		opt = opts[0]
+	}
+	if e.Col > maxRightCellNumber {
+		return fmt.Errorf("InsertImage: column %d is right of %s",
+			e.Col, maxRightCell)
+	}
+	file, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("InsertImage: %w", err)
+	}
+	config, _, err := image.DecodeConfig(file)
+	file.Close()
+	if err != nil {
+		return fmt.Errorf("InsertImage: %s: %w", path, err)
+	}
+	if config.Width == 0 || config.Height == 0 {
+		return fmt.Errorf("InsertImage: %s: empty image", path)
+	}
+
+	// maxRightCell までの幅に収まるように縮小する
+	width := 0.0
+	for col := e.Col; col <= maxRightCellNumber; col++ {
+		px, err := e.colWidthPixels(col)
+		if err != nil {
+			return fmt.Errorf("InsertImage: %w", err)
+		}
+		width += px
+	}
+	scale := math.Min(1, width/float64(config.Width))
+	cell, err := e.Cell()
+	if err != nil {
+		return fmt.Errorf("InsertImage: %w", err)
+	}
+	if err := e.f.AddPicture(e.sheet, cell, path, &excelize.GraphicOptions{
+		AltText:     opt.Caption,
+		ScaleX:      scale,
+		ScaleY:      scale,
+		Positioning: "oneCell",
+	}); err != nil {
+		return fmt.Errorf("InsertImage: %w", err)
+	}
+
+	// 画像の右下のセル
+	col2, imgWidth := e.Col, float64(config.Width)*scale
+	for w := 0.0; col2 < maxRightCellNumber; col2++ {
+		px, err := e.colWidthPixels(col2)
+		if err != nil {
+			return fmt.Errorf("InsertImage: %w", err)
+		}
+		if w += px; w >= imgWidth {
+			break
+		}
+	}
+	row2, imgHeight := e.Row, float64(config.Height)*scale
+	for h := 0.0; ; row2++ {
+		points, err := e.f.GetRowHeight(e.sheet, row2)
+		if err != nil {
+			return fmt.Errorf("InsertImage: %w", err)
+		}
+		if h += math.Ceil(4.0 / 3.4 * points); h >= imgHeight {
+			break
+		}
+	}
+	if opt.Border && (e.Col != col2 || e.Row != row2) {
+		cell2, err := excelize.CoordinatesToCellName(col2, row2)
+		if err != nil {
+			return fmt.Errorf("InsertImage: %w", err)
+		}
+		if err := e.DrawBorders(cell, cell2,
+			BorderContinuousWeight1); err != nil {
+			return fmt.Errorf("InsertImage: %w", err)
+		}
+	}
+	e.Row = row2 + 1
+
+	if opt.Caption == "" {
+		return nil
+	}
+	if err := e.Caption(CaptionFigure, opt.Caption, opt.ID); err != nil {
+		return fmt.Errorf("InsertImage: %w", err)
+	}
+	return nil
+}
+
+// colWidthPixels returns the width of the column in pixels, in the same
+// way as excelize places pictures.
+func (e *Excel) colWidthPixels(col int) (float64, error) {
+	name, err := excelize.ColumnNumberToName(col)
+	if err != nil {
+		return 0, err
+	}
+	width, err := e.f.GetColWidth(e.sheet, name)
+	if err != nil {
+		return 0, err
+	}
+	return float64(int(width*8 + 0.5)), nil
+}
@@ -1,5 +1,5 @@
-//go:generate stringer -type CellType -trimprefix CellType const.go
-package main
+//go:generate stringer -type CellType -trimprefix CellType celltype.go
+package inspect
 
 // CellType is the type of cell value type.
 type CellType byte
@@ -0,0 +1,788 @@
+// Package inspect collects the information of a workbook, such as the
+// properties, the page setup, the comments and the cells, in a stable schema
+// for JSON and YAML.
+package inspect
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/nonsugar-go/tools/excel"
+	"github.com/xuri/excelize/v2"
+)
+
+// schemaVersion is the version of the JSON/YAML schema. It is incremented
+// when a field is removed or changes its meaning.
+const schemaVersion = 1
+
+// Info is the information of a workbook.
+//
+// Optional properties which are not set in the workbook are nil. A section
+// which cannot be read is left empty and the reason is added to Warnings,
+// so that a workbook saved by other applications can still be inspected.
+type Info struct {
+	SchemaVersion int            `json:"schemaVersion" yaml:"schemaVersion"`
+	File          string         `json:"file" yaml:"file"`
+	Workbook      WorkbookProps  `json:"workbook" yaml:"workbook"`
+	DefinedNames  []DefinedName  `json:"definedNames" yaml:"definedNames"`
+	SheetList     []SheetEntry   `json:"sheetList" yaml:"sheetList"`
+	Sheets        []SheetDetails `json:"sheets" yaml:"sheets"`
+	Warnings      []string       `json:"warnings" yaml:"warnings"`
+}
+
+// WorkbookProps is the properties of a workbook.
+type WorkbookProps struct {
+	DefaultFont   *string `json:"defaultFont" yaml:"defaultFont"`
+	CodeName      *string `json:"codeName" yaml:"codeName"`
+	Date1904      *bool   `json:"date1904" yaml:"date1904"`
+	FilterPrivacy *bool   `json:"filterPrivacy" yaml:"filterPrivacy"`
+}
+
+// DefinedName is a defined name of a workbook.
+type DefinedName struct {
+	Name     string `json:"name" yaml:"name"`
+	Comment  string `json:"comment" yaml:"comment"`
+	RefersTo string `json:"refersTo" yaml:"refersTo"`
+	Scope    string `json:"scope" yaml:"scope"`
+}
+
+// SheetEntry is an entry of the sheet list.
+type SheetEntry struct {
+	Index int    `json:"index" yaml:"index"`
+	Name  string `json:"name" yaml:"name"`
+}
+
+// SheetDetails is the detailed information of a sheet.
+type SheetDetails struct {
+	Index        int          `json:"index" yaml:"index"`
+	Name         string       `json:"name" yaml:"name"`
+	Summary      SheetSummary `json:"summary" yaml:"summary"`
+	Props        SheetProps   `json:"props" yaml:"props"`
+	PageLayout   PageLayout   `json:"pageLayout" yaml:"pageLayout"`
+	PageMargins  PageMargins  `json:"pageMargins" yaml:"pageMargins"`
+	HeaderFooter HeaderFooter `json:"headerFooter" yaml:"headerFooter"`
+	Columns      []Column     `json:"columns" yaml:"columns"`
+	Rows         []Row        `json:"rows" yaml:"rows"`
+	Comments     []Comment    `json:"comments" yaml:"comments"`
+	MergedRanges []string     `json:"mergedRanges" yaml:"mergedRanges"`
+	Cells        []Cell       `json:"cells" yaml:"cells"`
+	Warnings     []string     `json:"warnings" yaml:"warnings"`
+}
+
+// SheetSummary is the summary of a sheet.
+type SheetSummary struct {
+	UsedRange    string        `json:"usedRange" yaml:"usedRange"`
+	LastColumn   int           `json:"lastColumn" yaml:"lastColumn"`
+	LastRow      int           `json:"lastRow" yaml:"lastRow"`
+	MergedCells  int           `json:"mergedCells" yaml:"mergedCells"`
+	Comments     int           `json:"comments" yaml:"comments"`
+	HeaderMarks  []HeaderMark  `json:"headerMarks" yaml:"headerMarks"`
+	Tables       []Table       `json:"tables" yaml:"tables"`
+	DefinedNames []DefinedName `json:"definedNames" yaml:"definedNames"`
+}
+
+// HeaderMark is a header marked by MarkHeader of the excel package.
+type HeaderMark struct {
+	Cell  string `json:"cell" yaml:"cell"`
+	Level int    `json:"level" yaml:"level"`
+	Value string `json:"value" yaml:"value"`
+}
+
+// Table is a table of a sheet.
+type Table struct {
+	Name      string `json:"name" yaml:"name"`
+	Range     string `json:"range" yaml:"range"`
+	StyleName string `json:"styleName" yaml:"styleName"`
+}
+
+// SheetProps is the properties of a sheet.
+type SheetProps struct {
+	AutoPageBreaks                    *bool    `json:"autoPageBreaks" yaml:"autoPageBreaks"`
+	BaseColWidth                      *uint8   `json:"baseColWidth" yaml:"baseColWidth"`
+	CodeName                          *string  `json:"codeName" yaml:"codeName"`
+	CustomHeight                      *bool    `json:"customHeight" yaml:"customHeight"`
+	DefaultColWidth                   *float64 `json:"defaultColWidth" yaml:"defaultColWidth"`
+	DefaultRowHeight                  *float64 `json:"defaultRowHeight" yaml:"defaultRowHeight"`
+	EnableFormatConditionsCalculation *bool    `json:"enableFormatConditionsCalculation" yaml:"enableFormatConditionsCalculation"`
+	FitToPage                         *bool    `json:"fitToPage" yaml:"fitToPage"`
+	OutlineSummaryBelow               *bool    `json:"outlineSummaryBelow" yaml:"outlineSummaryBelow"`
+	OutlineSummaryRight               *bool    `json:"outlineSummaryRight" yaml:"outlineSummaryRight"`
+	Published                         *bool    `json:"published" yaml:"published"`
+	TabColorIndexed                   *int     `json:"tabColorIndexed" yaml:"tabColorIndexed"`
+	TabColorRGB                       *string  `json:"tabColorRGB" yaml:"tabColorRGB"`
+	TabColorTheme                     *int     `json:"tabColorTheme" yaml:"tabColorTheme"`
+	TabColorTint                      *float64 `json:"tabColorTint" yaml:"tabColorTint"`
+	ThickBottom                       *bool    `json:"thickBottom" yaml:"thickBottom"`
+	ThickTop                          *bool    `json:"thickTop" yaml:"thickTop"`
+	ZeroHeight                        *bool    `json:"zeroHeight" yaml:"zeroHeight"`
+}
+
+// PageLayout is the page layout of a sheet.
+type PageLayout struct {
+	AdjustTo        *uint   `json:"adjustTo" yaml:"adjustTo"`
+	BlackAndWhite   *bool   `json:"blackAndWhite" yaml:"blackAndWhite"`
+	FirstPageNumber *uint   `json:"firstPageNumber" yaml:"firstPageNumber"`
+	FitToHeight     *int    `json:"fitToHeight" yaml:"fitToHeight"`
+	FitToWidth      *int    `json:"fitToWidth" yaml:"fitToWidth"`
+	Orientation     *string `json:"orientation" yaml:"orientation"`
+	PageOrder       *string `json:"pageOrder" yaml:"pageOrder"`
+	Size            *int    `json:"size" yaml:"size"`
+}
+
+// PageMargins is the page margins of a sheet in inches.
+type PageMargins struct {
+	Bottom       *float64 `json:"bottom" yaml:"bottom"`
+	Footer       *float64 `json:"footer" yaml:"footer"`
+	Header       *float64 `json:"header" yaml:"header"`
+	Horizontally *bool    `json:"horizontally" yaml:"horizontally"`
+	Left         *float64 `json:"left" yaml:"left"`
+	Right        *float64 `json:"right" yaml:"right"`
+	Top          *float64 `json:"top" yaml:"top"`
+	Vertically   *bool    `json:"vertically" yaml:"vertically"`
+}
+
+// HeaderFooter is the header and footer of a sheet.
+type HeaderFooter struct {
+	AlignWithMargins *bool  `json:"alignWithMargins" yaml:"alignWithMargins"`
+	DifferentFirst   bool   `json:"differentFirst" yaml:"differentFirst"`
+	DifferentOddEven bool   `json:"differentOddEven" yaml:"differentOddEven"`
+	EvenFooter       string `json:"evenFooter" yaml:"evenFooter"`
+	EvenHeader       string `json:"evenHeader" yaml:"evenHeader"`
+	FirstFooter      string `json:"firstFooter" yaml:"firstFooter"`
+	FirstHeader      string `json:"firstHeader" yaml:"firstHeader"`
+	OddFooter        string `json:"oddFooter" yaml:"oddFooter"`
+	OddHeader        string `json:"oddHeader" yaml:"oddHeader"`
+	ScaleWithDoc     *bool  `json:"scaleWithDoc" yaml:"scaleWithDoc"`
+}
+
+// Column is the properties of a column.
+type Column struct {
+	Name    string  `json:"name" yaml:"name"`
+	Visible bool    `json:"visible" yaml:"visible"`
+	Width   float64 `json:"width" yaml:"width"`
+}
+
+// Row is the properties of a row.
+type Row struct {
+	Number  int     `json:"number" yaml:"number"`
+	Visible bool    `json:"visible" yaml:"visible"`
+	Height  float64 `json:"height" yaml:"height"`
+}
+
+// Comment is a comment of a cell.
+type Comment struct {
+	Cell      string      `json:"cell" yaml:"cell"`
+	Author    string      `json:"author" yaml:"author"`
+	AuthorID  int         `json:"authorID" yaml:"authorID"`
+	Text      string      `json:"text" yaml:"text"`
+	Width     uint        `json:"width" yaml:"width"`
+	Height    uint        `json:"height" yaml:"height"`
+	Paragraph []Paragraph `json:"paragraph" yaml:"paragraph"`
+}
+
+// Paragraph is a run of rich text in a comment.
+type Paragraph struct {
+	Font *Font  `json:"font" yaml:"font"`
+	Text string `json:"text" yaml:"text"`
+}
+
+// Cell is the value and the style of a cell.
+type Cell struct {
+	Cell      string `json:"cell" yaml:"cell"`
+	Value     string `json:"value" yaml:"value"`
+	Type      string `json:"type" yaml:"type"`
+	Merged    string `json:"merged" yaml:"merged"` // セルを含む結合範囲
+	HyperLink string `json:"hyperLink" yaml:"hyperLink"`
+	StyleID   int    `json:"styleID" yaml:"styleID"`
+	Style     *Style `json:"style" yaml:"style"`
+}
+
+// Style is the style of a cell. Flags are the style flags of the excel
+// package decoded from the style, and FlagsExact reports whether the flags
+// represent the whole style.
+type Style struct {
+	Flags        []string   `json:"flags" yaml:"flags"`
+	FlagsExact   bool       `json:"flagsExact" yaml:"flagsExact"`
+	Border       []Border   `json:"border" yaml:"border"`
+	Fill         Fill       `json:"fill" yaml:"fill"`
+	Font         *Font      `json:"font" yaml:"font"`
+	Alignment    *Alignment `json:"alignment" yaml:"alignment"`
+	NumFmt       int        `json:"numFmt" yaml:"numFmt"`
+	CustomNumFmt *string    `json:"customNumFmt" yaml:"customNumFmt"`
+}
+
+// Border is a border of a cell.
+type Border struct {
+	Type  string `json:"type" yaml:"type"`
+	Color string `json:"color" yaml:"color"`
+	Style int    `json:"style" yaml:"style"`
+}
+
+// Fill is the fill of a cell.
+type Fill struct {
+	Type    string   `json:"type" yaml:"type"`
+	Pattern int      `json:"pattern" yaml:"pattern"`
+	Color   []string `json:"color" yaml:"color"`
+	Shading int      `json:"shading" yaml:"shading"`
+}
+
+// Font is a font of a cell or a comment.
+type Font struct {
+	Bold      bool    `json:"bold" yaml:"bold"`
+	Italic    bool    `json:"italic" yaml:"italic"`
+	Underline string  `json:"underline" yaml:"underline"`
+	Strike    bool    `json:"strike" yaml:"strike"`
+	Family    string  `json:"family" yaml:"family"`
+	Size      float64 `json:"size" yaml:"size"`
+	Color     string  `json:"color" yaml:"color"`
+}
+
+// Alignment is the alignment of a cell.
+type Alignment struct {
+	Horizontal   string `json:"horizontal" yaml:"horizontal"`
+	Vertical     string `json:"vertical" yaml:"vertical"`
+	Indent       int    `json:"indent" yaml:"indent"`
+	ShrinkToFit  bool   `json:"shrinkToFit" yaml:"shrinkToFit"`
+	TextRotation int    `json:"textRotation" yaml:"textRotation"`
+	WrapText     bool   `json:"wrapText" yaml:"wrapText"`
+}
+
+// Options is the range of the information to collect.
+type Options struct {
+	SheetIdx  int  // 対象のシートのインデックス
+	AllSheets bool // SheetIdx の代わりに全てのシートを対象とする
+
+	// ColMax, RowMax が 0 の場合は使用されている範囲の最後の列、行
+	ColMin, ColMax, RowMin, RowMax int
+
+	// RangeRef が指定された場合は CellCol, CellRow の代わりに範囲のセル
+	CellCol, CellRow int
+	RangeRef         string
+	// AllCells が true の場合は使用されている範囲の全てのセル
+	AllCells bool
+}
+
+// Collect collects the information of the workbook. An error is returned
+// only when the options are invalid.
+func Collect(e *excel.Excel, filename string, opts Options) (*Info, error) {
+	f := e.GetFile()
+	info := &Info{
+		SchemaVersion: schemaVersion,
+		File:          filename,
+		DefinedNames:  []DefinedName{},
+		SheetList:     []SheetEntry{},
+		Sheets:        []SheetDetails{},
+		Warnings:      []string{},
+	}
+	warn := func(format string, a ...any) {
+		info.Warnings = append(info.Warnings, fmt.Sprintf(format, a...))
+	}
+
+	if font, err := get(f.GetDefaultFont); err != nil {
+		warn("default font が取得できません: %v", err)
+	} else {
+		info.Workbook.DefaultFont = &font
+	}
+	if wbProps, err := get(f.GetWorkbookProps); err != nil {
+		warn("workbook props が取得できません: %v", err)
+	} else {
+		info.Workbook.CodeName = wbProps.CodeName
+		info.Workbook.Date1904 = wbProps.Date1904
+		info.Workbook.FilterPrivacy = wbProps.FilterPrivacy
+	}
+
+	for _, dn := range f.GetDefinedName() {
+		info.DefinedNames = append(info.DefinedNames, DefinedName{
+			Name:     dn.Name,
+			Comment:  dn.Comment,
+			RefersTo: dn.RefersTo,
+			Scope:    dn.Scope,
+		})
+	}
+
+	for _, sheet := range f.GetSheetList() {
+		i, err := f.GetSheetIndex(sheet)
+		if err != nil {
+			warn("%s: sheet index が取得できません: %v", sheet, err)
+			continue
+		}
+		info.SheetList = append(info.SheetList, SheetEntry{Index: i, Name: sheet})
+	}
+
+	sheets := info.SheetList
+	if !opts.AllSheets {
+		sheet := f.GetSheetName(opts.SheetIdx)
+		if sheet == "" {
+			return nil, fmt.Errorf("sheet index %d のシートがありません",
+				opts.SheetIdx)
+		}
+		sheets = []SheetEntry{{Index: opts.SheetIdx, Name: sheet}}
+	}
+	cells, err := cellRange(opts)
+	if err != nil {
+		return nil, err
+	}
+	for _, s := range sheets {
+		info.Sheets = append(info.Sheets,
+			*collectSheet(e, s.Index, s.Name, info.DefinedNames, cells, opts))
+	}
+	return info, nil
+}
+
+// get calls fn and converts a panic into an error. excelize panics on
+// some workbooks which lack optional parts, such as xl/styles.xml.
+func get[T any](fn func() (T, error)) (v T, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("panic: %v", r)
+		}
+	}()
+	return fn()
+}
+
+// cellRect is a rectangle range of cells.
+type cellRect struct {
+	col1, row1, col2, row2 int
+}
+
+// cellRange returns the range of the cells to collect.
+func cellRange(opts Options) (*cellRect, error) {
+	if opts.RangeRef == "" {
+		return &cellRect{opts.CellCol, opts.CellRow, opts.CellCol, opts.CellRow}, nil
+	}
+	col1, row1, col2, row2, err := parseRange(opts.RangeRef)
+	if err != nil {
+		return nil, err
+	}
+	return &cellRect{col1, row1, col2, row2}, nil
+}
+
+// collectSheet collects the detailed information of the sheet.
+func collectSheet(e *excel.Excel, idx int, sheet string,
+	definedNames []DefinedName, cells *cellRect, opts Options) *SheetDetails {
+	f := e.GetFile()
+	d := &SheetDetails{
+		Index:        idx,
+		Name:         sheet,
+		Columns:      []Column{},
+		Rows:         []Row{},
+		Comments:     []Comment{},
+		MergedRanges: []string{},
+		Cells:        []Cell{},
+		Warnings:     []string{},
+	}
+	warn := func(format string, a ...any) {
+		d.Warnings = append(d.Warnings, fmt.Sprintf(format, a...))
+	}
+	d.Summary = collectSummary(e, sheet, definedNames, warn)
+
+	if shProps, err := get(func() (excelize.SheetPropsOptions, error) {
+		return f.GetSheetProps(sheet)
+	}); err != nil {
+		warn("sheet props が取得できません: %v", err)
+	} else {
+		d.Props = newSheetProps(&shProps)
+	}
+	if pageLayout, err := get(func() (excelize.PageLayoutOptions, error) {
+		return f.GetPageLayout(sheet)
+	}); err != nil {
+		warn("page layout が取得できません: %v", err)
+	} else {
+		d.PageLayout = newPageLayout(&pageLayout)
+	}
+	if pageMargins, err := get(func() (excelize.PageLayoutMarginsOptions, error) {
+		return f.GetPageMargins(sheet)
+	}); err != nil {
+		warn("page margins が取得できません: %v", err)
+	} else {
+		d.PageMargins = newPageMargins(&pageMargins)
+	}
+	if headerFooter, err := get(func() (*excelize.HeaderFooterOptions, error) {
+		return f.GetHeaderFooter(sheet)
+	}); err != nil {
+		warn("header footer が取得できません: %v", err)
+	} else if headerFooter != nil {
+		d.HeaderFooter = newHeaderFooter(headerFooter)
+	}
+
+	colMax, rowMax := opts.ColMax, opts.RowMax
+	if colMax == 0 {
+		colMax = d.Summary.LastColumn
+	}
+	if rowMax == 0 {
+		rowMax = d.Summary.LastRow
+	}
+	for col := opts.ColMin; col <= colMax; col++ {
+		colName, err := excel.ColumnNumberToName(col)
+		if err != nil {
+			warn("column %d: %v", col, err)
+			break
+		}
+		colVisible, _ := f.GetColVisible(sheet, colName)
+		colWidth, _ := f.GetColWidth(sheet, colName)
+		d.Columns = append(d.Columns,
+			Column{Name: colName, Visible: colVisible, Width: colWidth})
+	}
+
+	for row := opts.RowMin; row <= rowMax; row++ {
+		rowVisible, _ := f.GetRowVisible(sheet, row)
+		rowHeight, _ := f.GetRowHeight(sheet, row)
+		d.Rows = append(d.Rows,
+			Row{Number: row, Visible: rowVisible, Height: rowHeight})
+	}
+
+	if comments, err := get(func() ([]excelize.Comment, error) {
+		return f.GetComments(sheet)
+	}); err != nil {
+		warn("comments が取得できません: %v", err)
+	} else {
+		for _, comment := range comments {
+			d.Comments = append(d.Comments, newComment(&comment))
+		}
+	}
+
+	merged, err := mergedRanges(f, sheet)
+	if err != nil {
+		warn("%v", err)
+	}
+	for _, r := range merged {
+		d.MergedRanges = append(d.MergedRanges, r.ref)
+	}
+	if opts.AllCells {
+		cells = &cellRect{1, 1, d.Summary.LastColumn, d.Summary.LastRow}
+	}
+	for row := cells.row1; row <= cells.row2; row++ {
+		for col := cells.col1; col <= cells.col2; col++ {
+			cell, err := excel.CoordinatesToCellName(col, row)
+			if err != nil {
+				warn("cell R%dC%d: %v", row, col, err)
+				continue
+			}
+			c := collectCell(f, sheet, cell, warn)
+			c.Merged = merged.find(col, row)
+			d.Cells = append(d.Cells, *c)
+		}
+	}
+	return d
+}
+
+// newSheetProps converts the sheet properties of excelize.
+func newSheetProps(shProps *excelize.SheetPropsOptions) SheetProps {
+	return SheetProps{
+		AutoPageBreaks:                    shProps.AutoPageBreaks,
+		BaseColWidth:                      shProps.BaseColWidth,
+		CodeName:                          shProps.CodeName,
+		CustomHeight:                      shProps.CustomHeight,
+		DefaultColWidth:                   shProps.DefaultColWidth,
+		DefaultRowHeight:                  shProps.DefaultRowHeight,
+		EnableFormatConditionsCalculation: shProps.EnableFormatConditionsCalculation,
+		FitToPage:                         shProps.FitToPage,
+		OutlineSummaryBelow:               shProps.OutlineSummaryBelow,
+		OutlineSummaryRight:               shProps.OutlineSummaryRight,
+		Published:                         shProps.Published,
+		TabColorIndexed:                   shProps.TabColorIndexed,
+		TabColorRGB:                       shProps.TabColorRGB,
+		TabColorTheme:                     shProps.TabColorTheme,
+		TabColorTint:                      shProps.TabColorTint,
+		ThickBottom:                       shProps.ThickBottom,
+		ThickTop:                          shProps.ThickTop,
+		ZeroHeight:                        shProps.ZeroHeight,
+	}
+}
+
+// newPageLayout converts the page layout of excelize.
+func newPageLayout(pageLayout *excelize.PageLayoutOptions) PageLayout {
+	return PageLayout{
+		AdjustTo:        pageLayout.AdjustTo,
+		BlackAndWhite:   pageLayout.BlackAndWhite,
+		FirstPageNumber: pageLayout.FirstPageNumber,
+		FitToHeight:     pageLayout.FitToHeight,
+		FitToWidth:      pageLayout.FitToWidth,
+		Orientation:     pageLayout.Orientation,
+		PageOrder:       pageLayout.PageOrder,
+		Size:            pageLayout.Size,
+	}
+}
+
+// newPageMargins converts the page margins of excelize.
+func newPageMargins(pageMargins *excelize.PageLayoutMarginsOptions) PageMargins {
+	return PageMargins{
+		Bottom:       pageMargins.Bottom,
+		Footer:       pageMargins.Footer,
+		Header:       pageMargins.Header,
+		Horizontally: pageMargins.Horizontally,
+		Left:         pageMargins.Left,
+		Right:        pageMargins.Right,
+		Top:          pageMargins.Top,
+		Vertically:   pageMargins.Vertically,
+	}
+}
+
+// newHeaderFooter converts the header and footer of excelize.
+func newHeaderFooter(headerFooter *excelize.HeaderFooterOptions) HeaderFooter {
+	return HeaderFooter{
+		AlignWithMargins: headerFooter.AlignWithMargins,
+		DifferentFirst:   headerFooter.DifferentFirst,
+		DifferentOddEven: headerFooter.DifferentOddEven,
+		EvenFooter:       headerFooter.EvenFooter,
+		EvenHeader:       headerFooter.EvenHeader,
+		FirstFooter:      headerFooter.FirstFooter,
+		FirstHeader:      headerFooter.FirstHeader,
+		OddFooter:        headerFooter.OddFooter,
+		OddHeader:        headerFooter.OddHeader,
+		ScaleWithDoc:     headerFooter.ScaleWithDoc,
+	}
+}
+
+// newComment converts the comment of excelize. A comment saved without
+// rich text has no paragraphs.
+func newComment(comment *excelize.Comment) Comment {
+	c := Comment{
+		Cell:      comment.Cell,
+		Author:    comment.Author,
+		AuthorID:  comment.AuthorID,
+		Text:      comment.Text,
+		Width:     comment.Width,
+		Height:    comment.Height,
+		Paragraph: []Paragraph{},
+	}
+	for _, p := range comment.Paragraph {
+		c.Paragraph = append(c.Paragraph,
+			Paragraph{Font: newFont(p.Font), Text: p.Text})
+	}
+	return c
+}
+
+// parseRange parses a range such as "B3:F20" or "B3".
+func parseRange(rangeRef string) (col1, row1, col2, row2 int, err error) {
+	first, last, ok := strings.Cut(rangeRef, ":")
+	if !ok {
+		last = first
+	}
+	if col1, row1, err = excelize.CellNameToCoordinates(first); err != nil {
+		return 0, 0, 0, 0, fmt.Errorf("range %s: %w", rangeRef, err)
+	}
+	if col2, row2, err = excelize.CellNameToCoordinates(last); err != nil {
+		return 0, 0, 0, 0, fmt.Errorf("range %s: %w", rangeRef, err)
+	}
+	if col2 < col1 {
+		col1, col2 = col2, col1
+	}
+	if row2 < row1 {
+		row1, row2 = row2, row1
+	}
+	return col1, row1, col2, row2, nil
+}
+
+// mergedRange is a merged range of cells.
+type mergedRange struct {
+	ref                    string
+	col1, row1, col2, row2 int
+}
+
+// mergedRangeList is the merged ranges of a sheet.
+type mergedRangeList []mergedRange
+
+// mergedRanges returns the merged ranges of the sheet.
+func mergedRanges(f *excelize.File, sheet string) (mergedRangeList, error) {
+	mergeCells, err := f.GetMergeCells(sheet, true)
+	if err != nil {
+		return nil, fmt.Errorf("merged cells が取得できません: %w", err)
+	}
+	list := make(mergedRangeList, 0, len(mergeCells))
+	for _, mc := range mergeCells {
+		ref := mc.GetStartAxis() + ":" + mc.GetEndAxis()
+		col1, row1, col2, row2, err := parseRange(ref)
+		if err != nil {
+			return nil, err
+		}
+		list = append(list, mergedRange{ref, col1, row1, col2, row2})
+	}
+	return list, nil
+}
+
+// find returns the merged range which contains the cell, or "" if none.
+func (l mergedRangeList) find(col, row int) string {
+	for _, r := range l {
+		if r.col1 <= col && col <= r.col2 && r.row1 <= row && row <= r.row2 {
+			return r.ref
+		}
+	}
+	return ""
+}
+
+// collectSummary collects the summary of the sheet. The defined names whose
+// scope is the sheet are picked from definedNames.
+func collectSummary(e *excel.Excel, sheet string, definedNames []DefinedName,
+	warn func(format string, a ...any)) SheetSummary {
+	f := e.GetFile()
+	s := SheetSummary{
+		HeaderMarks:  []HeaderMark{},
+		Tables:       []Table{},
+		DefinedNames: []DefinedName{},
+	}
+
+	var err error
+	if s.LastColumn, err = get(func() (int, error) {
+		return e.GetLastColumnNumber(sheet)
+	}); err != nil {
+		warn("最後の列が取得できません: %v", err)
+	}
+	if s.LastRow, err = get(func() (int, error) {
+		return e.GetLastRowNumber(sheet)
+	}); err != nil {
+		warn("最後の行が取得できません: %v", err)
+	}
+	if s.LastColumn > 0 && s.LastRow > 0 {
+		if cell, err := excel.CoordinatesToCellName(
+			s.LastColumn, s.LastRow); err != nil {
+			warn("使用されている範囲が求められません: %v", err)
+		} else {
+			s.UsedRange = "A1:" + cell
+		}
+	}
+
+	if mergedCells, err := get(func() ([]excelize.MergeCell, error) {
+		return f.GetMergeCells(sheet, true)
+	}); err != nil {
+		warn("merged cells が取得できません: %v", err)
+	} else {
+		s.MergedCells = len(mergedCells)
+	}
+
+	if comments, err := get(func() ([]excelize.Comment, error) {
+		return f.GetComments(sheet)
+	}); err != nil {
+		warn("comments が取得できません: %v", err)
+	} else {
+		s.Comments = len(comments)
+	}
+
+	if marks, err := get(func() ([]excel.HeaderMark, error) {
+		return e.GetHeaderMarks(sheet)
+	}); err != nil {
+		warn("header marks が取得できません: %v", err)
+	} else {
+		for _, m := range marks {
+			s.HeaderMarks = append(s.HeaderMarks,
+				HeaderMark{Cell: m.Cell, Level: m.Level, Value: m.Value})
+		}
+	}
+
+	if tables, err := get(func() ([]excelize.Table, error) {
+		return f.GetTables(sheet)
+	}); err != nil {
+		warn("tables が取得できません: %v", err)
+	} else {
+		for _, t := range tables {
+			s.Tables = append(s.Tables,
+				Table{Name: t.Name, Range: t.Range, StyleName: t.StyleName})
+		}
+	}
+
+	for _, dn := range definedNames {
+		if dn.Scope == sheet {
+			s.DefinedNames = append(s.DefinedNames, dn)
+		}
+	}
+	return s
+}
+
+// collectCell collects the value and the style of the cell. An attribute
+// which cannot be read is left empty.
+func collectCell(f *excelize.File, sheet, cell string,
+	warn func(format string, a ...any)) *Cell {
+	c := &Cell{Cell: cell, Type: CellTypeUnset.String()}
+	var err error
+	if c.Value, err = get(func() (string, error) {
+		return f.GetCellValue(sheet, cell)
+	}); err != nil {
+		warn("%s の値が取得できません: %v", cell, err)
+	}
+	if cellType, err := get(func() (excelize.CellType, error) {
+		return f.GetCellType(sheet, cell)
+	}); err != nil {
+		warn("%s の型が取得できません: %v", cell, err)
+	} else {
+		c.Type = CellType(cellType).String()
+	}
+	if c.HyperLink, err = get(func() (string, error) {
+		_, link, err := f.GetCellHyperLink(sheet, cell)
+		return link, err
+	}); err != nil {
+		warn("%s のハイパーリンクが取得できません: %v", cell, err)
+	}
+	if c.StyleID, err = get(func() (int, error) {
+		return f.GetCellStyle(sheet, cell)
+	}); err != nil {
+		warn("%s のスタイルが取得できません: %v", cell, err)
+		return c
+	}
+	if style, err := get(func() (*excelize.Style, error) {
+		return f.GetStyle(c.StyleID)
+	}); err != nil {
+		warn("%s: style %d が取得できません: %v", cell, c.StyleID, err)
+	} else {
+		c.Style = newStyle(style)
+	}
+	return c
+}
+
+// newStyle converts the style of excelize.
+func newStyle(style *excelize.Style) *Style {
+	if style == nil {
+		return nil
+	}
+	flags, exact := excel.StyleNames(style)
+	s := &Style{
+		Flags:      flags,
+		FlagsExact: exact,
+		Border:     []Border{},
+		Fill: Fill{
+			Type:    style.Fill.Type,
+			Pattern: style.Fill.Pattern,
+			Color:   style.Fill.Color,
+			Shading: style.Fill.Shading,
+		},
+		Font:         newFont(style.Font),
+		NumFmt:       style.NumFmt,
+		CustomNumFmt: style.CustomNumFmt,
+	}
+	if s.Flags == nil {
+		s.Flags = []string{}
+	}
+	if s.Fill.Color == nil {
+		s.Fill.Color = []string{}
+	}
+	for _, b := range style.Border {
+		s.Border = append(s.Border,
+			Border{Type: b.Type, Color: b.Color, Style: b.Style})
+	}
+	if a := style.Alignment; a != nil {
+		s.Alignment = &Alignment{
+			Horizontal:   a.Horizontal,
+			Vertical:     a.Vertical,
+			Indent:       a.Indent,
+			ShrinkToFit:  a.ShrinkToFit,
+			TextRotation: a.TextRotation,
+			WrapText:     a.WrapText,
+		}
+	}
+	return s
+}
+
+// newFont converts the font of excelize.
+func newFont(font *excelize.Font) *Font {
+	if font == nil {
+		return nil
+	}
+	return &Font{
+		Bold:      font.Bold,
+		Italic:    font.Italic,
+		Underline: font.Underline,
+		Strike:    font.Strike,
+		Family:    font.Family,
+		Size:      font.Size,
+		Color:     font.Color,
+	}
+}
@@ -0,0 +1,44 @@
+package inspect
+
+import (
+	"testing"
+
+	"github.com/nonsugar-go/tools/excel"
+)
+
+func TestCollect(t *testing.T) {
+	e, err := excel.OpenExcel("../../testdata/Sample1.xlsx")
+	if err != nil {
+		t.Fatalf("OpenExcel: want no error, but %v", err)
+	}
+	defer e.Close()
+	info, err := Collect(e, "Sample1.xlsx",
+		Options{SheetIdx: 3, ColMin: 1, RowMin: 1, AllCells: true})
+	if err != nil {
+		t.Fatalf("Collect: want no error, but %v", err)
+	}
+	if len(info.Sheets) != 1 || info.Sheets[0].Name != "ヘッダのサンプル" {
+		t.Fatalf("Collect: want sheet ヘッダのサンプル, but %v", info.Sheets)
+	}
+	d := info.Sheets[0]
+	if want := d.Summary.LastColumn * d.Summary.LastRow; len(d.Cells) != want {
+		t.Errorf("AllCells: want %d cells, but %d", want, len(d.Cells))
+	}
+	if len(d.Summary.HeaderMarks) != 7 {
+		t.Errorf("HeaderMarks: want 7, but %v", d.Summary.HeaderMarks)
+	}
+
+	tests := []struct {
+		name string
+		opts Options
+	}{
+		{"no such sheet", Options{SheetIdx: 99}},
+		{"invalid range", Options{RangeRef: "B3:XX"}},
+		{"invalid cell", Options{RangeRef: "0"}},
+	}
+	for _, tt := range tests {
+		if _, err := Collect(e, "Sample1.xlsx", tt.opts); err == nil {
+			t.Errorf("%s: want error, but %v", tt.name, err)
+		}
+	}
+}
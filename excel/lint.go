@@ -0,0 +1,412 @@
+package excel
+
+import (
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/xuri/excelize/v2"
+)
+
+// 規約の名前
+const (
+	ruleDefaultFont = "default-font" // デフォルトのフォントとサイズ
+	rulePageSize    = "page-size"    // 用紙サイズ=A4
+	ruleOrientation = "orientation"  // 印刷の向き=縦
+	ruleMargins     = "margins"      // 印刷マージン
+	ruleFooter      = "footer"       // フッタのページ番号
+	rulePrintTitles = "print-titles" // 印刷タイトル
+	ruleRightEdge   = "right-edge"   // 右端のセル (maxRightCell) より右の値
+	ruleHeading     = "heading"      // ヘッダの印とレベル
+	ruleTOC         = "toc"          // 目次とヘッダの一致
+)
+
+// Violation is a deviation from the conventions of the TOMATO documents.
+type Violation struct {
+	Sheet   string // シート名 (ブック全体の場合は空)
+	Cell    string // セル座標 (シート全体の場合は空)
+	Rule    string // 規約の名前 (例: "page-size")
+	Message string
+
+	fix func() error // 安全に修正できる場合の修正方法
+}
+
+// Location returns the location of the violation such as "Sheet1!A1".
+func (v Violation) Location() string {
+	switch {
+	case v.Sheet == "":
+		return "(workbook)"
+	case v.Cell == "":
+		return v.Sheet
+	}
+	return v.Sheet + "!" + v.Cell
+}
+
+// Fixable reports whether Fix can fix the violation safely.
+func (v Violation) Fixable() bool {
+	return v.fix != nil
+}
+
+// String returns the violation such as "Sheet1!A1: rule: message".
+func (v Violation) String() string {
+	return fmt.Sprintf("%s: %s: %s", v.Location(), v.Rule, v.Message)
+}
+
+// lintHeading is a header mark found by Lint.
+type lintHeading struct {
+	sheet string
+	HeaderMark
+}
+
+// Lint checks the workbook against the conventions which NewSheet applies:
+// A4 portrait, the margins, the print titles $1:$3, the page number in the
+// footer, the default font, header marks without level gaps, no values
+// right of maxRightCell and the table of contents matching the headers.
+//
+// The font size is the size given to New, or defaultFontSize by default.
+func (e *Excel) Lint(fontSize ...float64) ([]Violation, error) {
+	size := e.fontSize
+	if len(fontSize) > 0 {
+		size = fontSize[0]
+	}
+	if size == 0 {
+		size = defaultFontSize
+	}
+	violations := e.lintDefaultFont(size)
+	var headings []lintHeading
+	for _, sheet := range e.f.GetSheetList() {
+		v, err := e.lintPageSetup(sheet)
+		if err != nil {
+			return nil, fmt.Errorf("Lint: %w", err)
+		}
+		violations = append(violations, v...)
+		if v, err = e.lintRightEdge(sheet); err != nil {
+			return nil, fmt.Errorf("Lint: %w", err)
+		}
+		violations = append(violations, v...)
+		h, v, err := e.lintHeaderMarks(sheet)
+		if err != nil {
+			return nil, fmt.Errorf("Lint: %w", err)
+		}
+		violations = append(violations, v...)
+		headings = append(headings, h...)
+	}
+
+	// ヘッダのレベルは 1 つずつ深くする (例: レベル1 の次にレベル3 は不可)
+	prev := 0
+	for _, h := range headings {
+		if h.Level > prev+1 {
+			violations = append(violations, Violation{
+				Sheet: h.sheet, Cell: h.Cell, Rule: ruleHeading,
+				Message: fmt.Sprintf("level %d header %q follows level %d",
+					h.Level, h.Value, prev),
+			})
+		}
+		prev = h.Level
+	}
+
+	v, err := e.lintTOC(headings)
+	if err != nil {
+		return nil, fmt.Errorf("Lint: %w", err)
+	}
+	return append(violations, v...), nil
+}
+
+// Fix fixes the fixable violations, and returns the number of the fixed
+// violations. The workbook is not saved.
+func (e *Excel) Fix(violations []Violation) (int, error) {
+	fixed := 0
+	for _, v := range violations {
+		if !v.Fixable() {
+			continue
+		}
+		if err := v.fix(); err != nil {
+			return fixed, fmt.Errorf("Fix: %s: %w", v, err)
+		}
+		fixed++
+	}
+	return fixed, nil
+}
+
+// lintDefaultFont checks the default font and its size.
+func (e *Excel) lintDefaultFont(size float64) []Violation {
+	style, err := e.f.GetStyle(0)
+	if err != nil || style.Font == nil {
+		// スタイルがないブックは、修正しない
+		return []Violation{{Rule: ruleDefaultFont,
+			Message: "the default font is not set"}}
+	}
+	if style.Font.Family == defaultFont && style.Font.Size == size {
+		return nil
+	}
+	return []Violation{{
+		Rule: ruleDefaultFont,
+		Message: fmt.Sprintf("%s %gpt, want %s %gpt",
+			style.Font.Family, style.Font.Size, defaultFont, size),
+		fix: func() error { return e.f.SetDefaultFont(defaultFont, size) },
+	}}
+}
+
+// lintPageSetup checks the page layout, the margins, the footer and the
+// print titles of the sheet.
+func (e *Excel) lintPageSetup(sheet string) ([]Violation, error) {
+	var violations []Violation
+	add := func(rule, message string, fix func() error) {
+		violations = append(violations, Violation{Sheet: sheet, Rule: rule,
+			Message: message, fix: fix})
+	}
+	want := tomatoPageLayout()
+	layout, err := e.f.GetPageLayout(sheet)
+	if err != nil {
+		return nil, err
+	}
+	if layout.Size == nil || *layout.Size != *want.Size {
+		add(rulePageSize, fmt.Sprintf("paper size %s, want %d (A4)",
+			ptrString(layout.Size), *want.Size), func() error {
+			return e.f.SetPageLayout(sheet,
+				&excelize.PageLayoutOptions{Size: want.Size})
+		})
+	}
+	// 未設定の場合は縦
+	if layout.Orientation != nil && *layout.Orientation != *want.Orientation {
+		add(ruleOrientation, fmt.Sprintf("orientation %s, want %s",
+			*layout.Orientation, *want.Orientation), func() error {
+			return e.f.SetPageLayout(sheet,
+				&excelize.PageLayoutOptions{Orientation: want.Orientation})
+		})
+	}
+
+	wantMargins := tomatoPageMargins()
+	margins, err := e.f.GetPageMargins(sheet)
+	if err != nil {
+		return nil, err
+	}
+	var diffs []string
+	for _, m := range []struct {
+		name      string
+		got, want *float64
+	}{
+		{"top", margins.Top, wantMargins.Top},
+		{"bottom", margins.Bottom, wantMargins.Bottom},
+		{"left", margins.Left, wantMargins.Left},
+		{"right", margins.Right, wantMargins.Right},
+		{"header", margins.Header, wantMargins.Header},
+		{"footer", margins.Footer, wantMargins.Footer},
+	} {
+		if m.got == nil || math.Abs(*m.got-*m.want) > 1e-6 {
+			diffs = append(diffs, fmt.Sprintf("%s %s, want %.2f",
+				m.name, ptrString(m.got), *m.want))
+		}
+	}
+	if len(diffs) > 0 {
+		add(ruleMargins, strings.Join(diffs, "; "), func() error {
+			return e.f.SetPageMargins(sheet, wantMargins)
+		})
+	}
+
+	hf, err := e.f.GetHeaderFooter(sheet)
+	if err != nil {
+		return nil, err
+	}
+	if hf == nil {
+		hf = &excelize.HeaderFooterOptions{}
+	}
+	if wantFooter := tomatoHeaderFooter().OddFooter; hf.OddFooter != wantFooter {
+		add(ruleFooter, fmt.Sprintf("footer %q, want %q",
+			hf.OddFooter, wantFooter), func() error {
+			hf.OddFooter = wantFooter
+			return e.f.SetHeaderFooter(sheet, hf)
+		})
+	}
+
+	if sheet == coverSheet {
+		// 表紙には印刷タイトルがない
+		return violations, nil
+	}
+	titles := tomatoPrintTitles(sheet)
+	var current *excelize.DefinedName
+	for _, dn := range e.f.GetDefinedName() {
+		if dn.Name == titles.Name && dn.Scope == sheet {
+			current = &dn
+			break
+		}
+	}
+	if current == nil {
+		add(rulePrintTitles, "no print titles, want $1:$3", func() error {
+			return e.f.SetDefinedName(titles)
+		})
+		return violations, nil
+	}
+	ref := current.RefersTo[strings.LastIndex(current.RefersTo, "!")+1:]
+	if ref != "$1:$3" {
+		add(rulePrintTitles, fmt.Sprintf("print titles %s, want $1:$3",
+			current.RefersTo), func() error {
+			if err := e.f.DeleteDefinedName(current); err != nil {
+				return err
+			}
+			return e.f.SetDefinedName(titles)
+		})
+	}
+	return violations, nil
+}
+
+// lintRightEdge checks the values right of maxRightCell.
+func (e *Excel) lintRightEdge(sheet string) ([]Violation, error) {
+	rows, err := e.f.GetRows(sheet)
+	if err != nil {
+		return nil, err
+	}
+	var violations []Violation
+	for r, row := range rows {
+		for c := maxRightCellNumber; c < len(row); c++ {
+			if row[c] == "" {
+				continue
+			}
+			cell, err := excelize.CoordinatesToCellName(c+1, r+1)
+			if err != nil {
+				return nil, err
+			}
+			violations = append(violations, Violation{
+				Sheet: sheet, Cell: cell, Rule: ruleRightEdge,
+				Message: fmt.Sprintf("value %q is right of column %s",
+					row[c], maxRightCell),
+			})
+		}
+	}
+	return violations, nil
+}
+
+// lintHeaderMarks returns the header marks of the sheet, and the invalid
+// header marks as violations.
+func (e *Excel) lintHeaderMarks(sheet string) ([]lintHeading,
+	[]Violation, error) {
+	comments, err := e.GetSortedComments(sheet)
+	if err != nil {
+		return nil, nil, err
+	}
+	var (
+		headings   []lintHeading
+		violations []Violation
+	)
+	for _, comment := range comments {
+		for _, paragraph := range comment.Paragraph {
+			if !strings.HasPrefix(paragraph.Text, headerMark) {
+				continue
+			}
+			level, err := headerLevel(paragraph.Text)
+			if err != nil {
+				violations = append(violations, Violation{Sheet: sheet,
+					Cell: comment.Cell, Rule: ruleHeading, Message: err.Error()})
+				break
+			}
+			value, err := e.f.GetCellValue(sheet, comment.Cell)
+			if err != nil {
+				return nil, nil, err
+			}
+			headings = append(headings, lintHeading{sheet: sheet,
+				HeaderMark: HeaderMark{Cell: comment.Cell, Level: level,
+					Value: value}})
+			break
+		}
+	}
+	return headings, violations, nil
+}
+
+// lintTOC checks the entries of the table of contents made by MakeTOC
+// against the headers.
+func (e *Excel) lintTOC(headings []lintHeading) ([]Violation, error) {
+	if idx, err := e.f.GetSheetIndex(tocSheet); err != nil || idx == -1 {
+		return nil, err
+	}
+	comments, err := e.GetSortedComments(tocSheet)
+	if err != nil {
+		return nil, err
+	}
+	// 目次の範囲は開始コメントの行から終了コメントの行まで
+	beginRow, endRow := 0, 0
+	for _, comment := range comments {
+		_, row, err := excelize.SplitCellName(comment.Cell)
+		if err != nil {
+			return nil, err
+		}
+		for _, paragraph := range comment.Paragraph {
+			switch paragraph.Text {
+			case beginTableOfContents:
+				beginRow = row
+			case endTableOfContents:
+				endRow = row
+			}
+		}
+	}
+	if beginRow == 0 {
+		if len(headings) == 0 {
+			return nil, nil
+		}
+		return []Violation{{Sheet: tocSheet, Rule: ruleTOC,
+			Message: "no table of contents"}}, nil
+	}
+	endRow = max(endRow, beginRow)
+
+	type entry struct {
+		cell, value, link string
+		level             int
+	}
+	var entries []entry
+	for row := beginRow; row <= endRow; row++ {
+		for level := 1; level <= maxHeaderLevel; level++ {
+			cell, err := excelize.CoordinatesToCellName(level+1, row)
+			if err != nil {
+				return nil, err
+			}
+			value, err := e.f.GetCellValue(tocSheet, cell)
+			if err != nil {
+				return nil, err
+			}
+			if value == "" {
+				continue
+			}
+			_, link, err := e.f.GetCellHyperLink(tocSheet, cell)
+			if err != nil {
+				return nil, err
+			}
+			entries = append(entries, entry{cell, value, link, level})
+			break
+		}
+	}
+
+	var violations []Violation
+	for i, h := range headings {
+		cellAbs, err := RelCellNameToAbsCellName(h.Cell)
+		if err != nil {
+			return nil, err
+		}
+		link := fmt.Sprintf("'%s'!%s", h.sheet, cellAbs)
+		if i >= len(entries) {
+			violations = append(violations, Violation{Sheet: tocSheet,
+				Rule: ruleTOC, Message: fmt.Sprintf(
+					"no entry for header %q at %s", h.Value, link)})
+			continue
+		}
+		if ent := entries[i]; ent.value != h.Value || ent.level != h.Level ||
+			ent.link != link {
+			violations = append(violations, Violation{Sheet: tocSheet,
+				Cell: ent.cell, Rule: ruleTOC, Message: fmt.Sprintf(
+					"entry %q (level %d, %s) does not match header %q (level %d, %s)",
+					ent.value, ent.level, ent.link, h.Value, h.Level, link)})
+		}
+	}
+	for _, ent := range entries[min(len(headings), len(entries)):] {
+		violations = append(violations, Violation{Sheet: tocSheet,
+			Cell: ent.cell, Rule: ruleTOC,
+			Message: fmt.Sprintf("entry %q has no header", ent.value)})
+	}
+	return violations, nil
+}
+
+// ptrString formats the value of the pointer, or "unset" if it is nil.
+func ptrString[T any](p *T) string {
+	if p == nil {
+		return "unset"
+	}
+	return fmt.Sprint(*p)
+}
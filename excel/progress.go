@@ -0,0 +1,27 @@
+package excel
+
+// ProgressFunc is called during long operations, such as WriteDF, MakeTOC
+// and SaveAndClose. The step counts from 1 to total; total 0 means the
+// number of the steps is unknown. The label describes the operation.
+type ProgressFunc func(step, total int, label string)
+
+// SetProgress sets the progress hook. A nil hook removes it.
+//
+// Example:
+//
+//	err := tui.Progress("設定表を作成しています", func(
+//		report func(step, total int, label string)) error {
+//		e.SetProgress(report)
+//		...
+//		return e.SaveAndClose()
+//	})
+func (e *Excel) SetProgress(fn ProgressFunc) {
+	e.progress = fn
+}
+
+// reportProgress calls the progress hook if set.
+func (e *Excel) reportProgress(step, total int, label string) {
+	if e.progress != nil {
+		e.progress(step, total, label)
+	}
+}
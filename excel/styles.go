@@ -3,6 +3,7 @@ package excel
 import (
 	"errors"
 	"fmt"
+	"strings"
 
 	"github.com/xuri/excelize/v2"
 )
@@ -110,6 +111,11 @@ const (
 	bdashT // top border
 	bdashR // right border
 	bdashB // bottom border
+
+	// 既存のビットの値を変えないよう、未使用のビットに追加する
+	// (cellStyle のビットはこれで使い切り)
+	alignmentHorizontalRight                    // 横位置=右詰め "right"
+	alignmentHorizontalLeft  cellStyle = 1 << 0 // 横位置=左詰め "left"
 )
 
 type BorderType int
@@ -376,6 +382,52 @@ func (e *Excel) SetStyleForCell(cell string, style cellStyle) error {
 			Type: "pattern", Color: []string{colorCode}, Pattern: 1}
 	}
 
+	// 横位置について排他処理を実施
+	// 今回引数で追加したスタイルから先にチェックする
+
+	const (
+		alignmentHorizontalAll cellStyle = alignmentHorizontalLeft |
+			alignmentHorizontalCenter | alignmentHorizontalRight
+	)
+
+	style_copy = style
+	if style&alignmentHorizontalAll != 0 {
+		style &^= alignmentHorizontalAll
+		if add_style&alignmentHorizontalAll != 0 {
+			style_copy = add_style
+		}
+		for _, s := range []cellStyle{alignmentHorizontalLeft,
+			alignmentHorizontalCenter, alignmentHorizontalRight} {
+			if style_copy&s != 0 {
+				style |= s
+				break
+			}
+		}
+	}
+
+	// 文字の制御 (折り返し・縮小) について排他処理を実施
+	// 今回引数で追加したスタイルから先にチェックする
+
+	const (
+		alignmentTextControlAll cellStyle = alignmentShrinkToFit |
+			alignmentWrapText
+	)
+
+	style_copy = style
+	if style&alignmentTextControlAll != 0 {
+		style &^= alignmentTextControlAll
+		if add_style&alignmentTextControlAll != 0 {
+			style_copy = add_style
+		}
+		for _, s := range []cellStyle{alignmentWrapText,
+			alignmentShrinkToFit} {
+			if style_copy&s != 0 {
+				style |= s
+				break
+			}
+		}
+	}
+
 	// Alignment 配置
 	var (
 		varAlignmentHorizontal  = ""
@@ -383,8 +435,12 @@ func (e *Excel) SetStyleForCell(cell string, style cellStyle) error {
 		varAlignmentVertical    = ""
 		varAlignmentWrapText    = false
 	)
-	if style&alignmentHorizontalCenter != 0 {
+	if style&alignmentHorizontalLeft != 0 {
+		varAlignmentHorizontal = "left"
+	} else if style&alignmentHorizontalCenter != 0 {
 		varAlignmentHorizontal = "center"
+	} else if style&alignmentHorizontalRight != 0 {
+		varAlignmentHorizontal = "right"
 	}
 	if style&alignmentShrinkToFit != 0 {
 		varAlignmentShrinkToFit = true
@@ -468,40 +524,10 @@ func (e *Excel) SetStyleForCell(cell string, style cellStyle) error {
 
 	// Border
 	var border []excelize.Border
-
-	for _, bs := range []struct {
-		cellStyle cellStyle
-		typ       string
-		style     int
-	}{
-		{b1L, "left", 1},
-		{b1T, "top", 1},
-		{b1R, "right", 1},
-		{b1B, "bottom", 1},
-
-		{b2L, "left", 2},
-		{b2T, "top", 2},
-		{b2R, "right", 2},
-		{b2B, "bottom", 2},
-
-		{bdashL, "left", 3},
-		{bdashT, "top", 3},
-		{bdashR, "right", 3},
-		{bdashB, "bottom", 3},
-
-		{b3L, "left", 5},
-		{b3T, "top", 5},
-		{b3R, "right", 5},
-		{b3B, "bottom", 5},
-
-		{bdL, "left", 6},
-		{bdT, "top", 6},
-		{bdR, "right", 6},
-		{bdB, "bottom", 6},
-	} {
-		if style&bs.cellStyle != 0 {
+	for _, bs := range borderStyles {
+		if style&bs.style != 0 {
 			border = append(border, excelize.Border{
-				Type: bs.typ, Color: "000000", Style: bs.style})
+				Type: bs.typ, Color: "000000", Style: bs.excelStyle})
 		}
 	}
 
@@ -579,6 +605,320 @@ func (e *Excel) SetStyle(style cellStyle) error {
 	return e.SetStyleForCell(cell, style)
 }
 
+// fontColors lists the font color styles with their RGB color codes.
+var fontColors = []struct {
+	style cellStyle
+	code  string
+}{
+	{fontDeepRed, "C00000"},
+	{fontRed, "FF0000"},
+	{fontOrange, "FFC000"},
+	{fontYellow, "FFFF00"},
+	{fontLightGreen, "92D050"},
+	{fontGreen, "00B050"},
+	{fontLightBlue, "00B0F0"},
+	{fontBlue, "0070C0"},
+	{fontDarkBlue, "002060"},
+	{fontPurple, "7030A0"},
+	{fontHyperLink, "0563C1"},
+}
+
+// fillColors lists the fill styles with their RGB color codes.
+var fillColors = []struct {
+	style cellStyle
+	code  string
+}{
+	{fillDeepRed, "C00000"},
+	{fillRed, "FF0000"},
+	{fillOrange, "FFC000"},
+	{fillYellow, "FFFF00"},
+	{fillLightGreen, "92D050"},
+	{fillGreen, "00B050"},
+	{fillLightBlue, "00B0F0"},
+	{fillBlue, "0070C0"},
+	{fillDarkBlue, "002060"},
+	{fillPurple, "7030A0"},
+	{fillGray1, "808080"},
+	{fillGray2, "A6A6A6"},
+	{fillGray3, "BFBFBF"},
+	{fillGray4, "D9D9D9"},
+	{fillGray5, "F2F2F2"},
+	{fillHeaderColor1, "808080"},
+	{fillHeaderColor2, "969696"},
+	{fillHeaderColor3, "C0C0C0"},
+	{fillCaution, "FF00FF"},
+	{fillNote, "FFFF00"},
+	{fillHint, "00FFFF"},
+}
+
+// borderStyles lists the border styles with the border type and the border
+// style of excelize.
+var borderStyles = []struct {
+	style      cellStyle
+	typ        string
+	excelStyle int
+}{
+	{b1L, "left", 1},
+	{b1T, "top", 1},
+	{b1R, "right", 1},
+	{b1B, "bottom", 1},
+
+	{b2L, "left", 2},
+	{b2T, "top", 2},
+	{b2R, "right", 2},
+	{b2B, "bottom", 2},
+
+	{bdashL, "left", 3},
+	{bdashT, "top", 3},
+	{bdashR, "right", 3},
+	{bdashB, "bottom", 3},
+
+	{b3L, "left", 5},
+	{b3T, "top", 5},
+	{b3R, "right", 5},
+	{b3B, "bottom", 5},
+
+	{bdL, "left", 6},
+	{bdT, "top", 6},
+	{bdR, "right", 6},
+	{bdB, "bottom", 6},
+}
+
+// styleNames lists the names of the style flags in bit order.
+var styleNames = []struct {
+	style cellStyle
+	name  string
+}{
+	{fontFamilyYuGothic, "fontFamilyYuGothic"},
+	{fontFamilyMSGothic, "fontFamilyMSGothic"},
+	{fontSize10, "fontSize10"},
+	{fontSize12, "fontSize12"},
+	{fontSize20, "fontSize20"},
+	{fontBold, "fontBold"},
+	{fontDeepRed, "fontDeepRed"},
+	{fontRed, "fontRed"},
+	{fontOrange, "fontOrange"},
+	{fontYellow, "fontYellow"},
+	{fontLightGreen, "fontLightGreen"},
+	{fontGreen, "fontGreen"},
+	{fontLightBlue, "fontLightBlue"},
+	{fontBlue, "fontBlue"},
+	{fontDarkBlue, "fontDarkBlue"},
+	{fontPurple, "fontPurple"},
+	{fontHyperLink, "fontHyperLink"},
+	{fillDeepRed, "fillDeepRed"},
+	{fillRed, "fillRed"},
+	{fillOrange, "fillOrange"},
+	{fillYellow, "fillYellow"},
+	{fillLightGreen, "fillLightGreen"},
+	{fillGreen, "fillGreen"},
+	{fillLightBlue, "fillLightBlue"},
+	{fillBlue, "fillBlue"},
+	{fillDarkBlue, "fillDarkBlue"},
+	{fillPurple, "fillPurple"},
+	{fillGray1, "fillGray1"},
+	{fillGray2, "fillGray2"},
+	{fillGray3, "fillGray3"},
+	{fillGray4, "fillGray4"},
+	{fillGray5, "fillGray5"},
+	{fillHeaderColor1, "fillHeaderColor1"},
+	{fillHeaderColor2, "fillHeaderColor2"},
+	{fillHeaderColor3, "fillHeaderColor3"},
+	{fillCaution, "fillCaution"},
+	{fillNote, "fillNote"},
+	{fillHint, "fillHint"},
+	{alignmentHorizontalLeft, "alignmentHorizontalLeft"},
+	{alignmentHorizontalCenter, "alignmentHorizontalCenter"},
+	{alignmentHorizontalRight, "alignmentHorizontalRight"},
+	{alignmentShrinkToFit, "alignmentShrinkToFit"},
+	{alignmentVerticalCenter, "alignmentVerticalCenter"},
+	{alignmentWrapText, "alignmentWrapText"},
+	{b1L, "b1L"},
+	{b1T, "b1T"},
+	{b1R, "b1R"},
+	{b1B, "b1B"},
+	{b2L, "b2L"},
+	{b2T, "b2T"},
+	{b2R, "b2R"},
+	{b2B, "b2B"},
+	{b3L, "b3L"},
+	{b3T, "b3T"},
+	{b3R, "b3R"},
+	{b3B, "b3B"},
+	{bdL, "bdL"},
+	{bdT, "bdT"},
+	{bdR, "bdR"},
+	{bdB, "bdB"},
+	{bdashL, "bdashL"},
+	{bdashT, "bdashT"},
+	{bdashR, "bdashR"},
+	{bdashB, "bdashB"},
+}
+
+// Names returns the names of the style flags, such as "fontBold" and "b1L".
+func (c cellStyle) Names() []string {
+	var names []string
+	for _, n := range styleNames {
+		if c&n.style != 0 {
+			names = append(names, n.name)
+		}
+	}
+	return names
+}
+
+// normalizeColor converts a color code such as "#c00000" or "FFC00000" to
+// the form of the palette, such as "C00000".
+func normalizeColor(code string) string {
+	code = strings.ToUpper(strings.TrimPrefix(code, "#"))
+	if len(code) == 8 {
+		code = code[2:]
+	}
+	return code
+}
+
+// StyleNames returns the names of the style flags of this package which
+// represent the style of excelize, such as "fontBold" and "b1L". It also
+// reports whether the flags represent the whole style; see decodeStyle.
+//
+// Example:
+//
+//	s, _ := e.GetFile().GetStyle(styleID)
+//	names, ok := StyleNames(s)
+//	fmt.Println(names, ok) // [fontBold b1L b1T] true
+func StyleNames(style *excelize.Style) ([]string, bool) {
+	c, ok := decodeStyle(style)
+	return c.Names(), ok
+}
+
+// decodeStyle converts a style of excelize back to the style flags of this
+// package. The font family and the font size are decoded only when they
+// match a style flag, because the others are the default font of the
+// workbook. It also reports whether all the other attributes of the style,
+// such as the fill color and the borders, are represented by the flags.
+// When the palette has several flags of the same color, the first one is
+// used, e.g. fillYellow rather than fillNote.
+func decodeStyle(style *excelize.Style) (cellStyle, bool) {
+	c, ok := styleNormal, true
+	if style == nil {
+		return c, ok
+	}
+
+	if font := style.Font; font != nil {
+		switch font.Family {
+		case "游ゴシック":
+			c |= fontFamilyYuGothic
+		case "ＭＳ ゴシック":
+			c |= fontFamilyMSGothic
+		}
+		switch font.Size {
+		case 10:
+			c |= fontSize10
+		case 12:
+			c |= fontSize12
+		case 20:
+			c |= fontSize20
+		}
+		if font.Bold {
+			c |= fontBold
+		}
+		if font.Color != "" {
+			code, found := normalizeColor(font.Color), false
+			for _, fc := range fontColors {
+				if fc.code == code {
+					c |= fc.style
+					found = true
+					break
+				}
+			}
+			// 黒 (自動) はフラグがなくても一致とみなす
+			if !found && code != "000000" {
+				ok = false
+			}
+		}
+		if font.Italic || font.Strike || font.Underline != "" {
+			ok = false
+		}
+	}
+
+	if fill := style.Fill; fill.Type != "" && fill.Pattern != 0 {
+		if fill.Type != "pattern" || fill.Pattern != 1 || len(fill.Color) != 1 {
+			ok = false
+		} else if s, err := fillStyle(normalizeColor(fill.Color[0])); err != nil {
+			ok = false
+		} else {
+			c |= s
+		}
+	}
+
+	if a := style.Alignment; a != nil {
+		switch a.Horizontal {
+		case "":
+		case "left":
+			c |= alignmentHorizontalLeft
+		case "center":
+			c |= alignmentHorizontalCenter
+		case "right":
+			c |= alignmentHorizontalRight
+		default:
+			ok = false
+		}
+		switch a.Vertical {
+		case "":
+		case "center":
+			c |= alignmentVerticalCenter
+		default:
+			ok = false
+		}
+		if a.ShrinkToFit {
+			c |= alignmentShrinkToFit
+		}
+		if a.WrapText {
+			c |= alignmentWrapText
+		}
+		if a.Indent != 0 || a.TextRotation != 0 {
+			ok = false
+		}
+	}
+
+BORDER:
+	for _, b := range style.Border {
+		if code := normalizeColor(b.Color); code != "" && code != "000000" {
+			ok = false
+		}
+		for _, bs := range borderStyles {
+			if bs.typ == b.Type && bs.excelStyle == b.Style {
+				c |= bs.style
+				continue BORDER
+			}
+		}
+		ok = false
+	}
+	return c, ok
+}
+
+// fillStyle returns the fill style of the RGB color code such as "C0C0C0".
+// Only the colors of the package palette are supported.
+func fillStyle(code string) (cellStyle, error) {
+	code = strings.ToUpper(strings.TrimPrefix(code, "#"))
+	for _, c := range fillColors {
+		if c.code == code {
+			return c.style, nil
+		}
+	}
+	return styleNormal, fmt.Errorf("unsupported fill color: %s", code)
+}
+
+// fontColorStyle returns the font color style of the RGB color code.
+func fontColorStyle(code string) (cellStyle, error) {
+	code = strings.ToUpper(strings.TrimPrefix(code, "#"))
+	for _, c := range fontColors {
+		if c.code == code {
+			return c.style, nil
+		}
+	}
+	return styleNormal, fmt.Errorf("unsupported font color: %s", code)
+}
+
 // NewStyle combines the default Normal cell style with additional styles.
 func NewStyle(styles ...cellStyle) cellStyle {
 	c := styleNormal
@@ -609,6 +949,64 @@ func (c cellStyle) Bold() cellStyle {
 	return c | fontBold
 }
 
+// Fill sets the fill color of the cell style by the RGB color code such as
+// "FF00FF". Colors which are not in the package palette are ignored; use
+// WithFill to check the color.
+//
+// Example:
+//
+//	style := NewStyle().Fill("FFFF00")
+func (c cellStyle) Fill(code string) cellStyle {
+	s, err := fillStyle(code)
+	if err != nil {
+		return c
+	}
+	return c | s
+}
+
+// WithFill is Fill which returns an error if the color is not in the
+// package palette, as WriteDFOptions.HeaderFillColor does.
+//
+// Example:
+//
+//	style, err := NewStyle().WithFill(color)
+func (c cellStyle) WithFill(code string) (cellStyle, error) {
+	s, err := fillStyle(code)
+	if err != nil {
+		return c, err
+	}
+	return c | s, nil
+}
+
+// FontColor sets the font color of the cell style by the RGB color code
+// such as "FF0000". Colors which are not in the package palette are ignored;
+// use WithFontColor to check the color.
+//
+// Example:
+//
+//	style := NewStyle().FontColor("FF0000").Bold()
+func (c cellStyle) FontColor(code string) cellStyle {
+	s, err := fontColorStyle(code)
+	if err != nil {
+		return c
+	}
+	return c | s
+}
+
+// WithFontColor is FontColor which returns an error if the color is not in
+// the package palette.
+//
+// Example:
+//
+//	style, err := NewStyle().Bold().WithFontColor(color)
+func (c cellStyle) WithFontColor(code string) (cellStyle, error) {
+	s, err := fontColorStyle(code)
+	if err != nil {
+		return c, err
+	}
+	return c | s, nil
+}
+
 // DrawBorders applies borders to a specified range of cells.
 //
 // BoderType:
@@ -0,0 +1,381 @@
+package excel
+
+import (
+	"fmt"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"text/template"
+
+	"github.com/xuri/excelize/v2"
+)
+
+var (
+	// 繰り返す範囲の開始と終了 (例: {{range .Policies}} … {{end}})
+	rangeBegin = regexp.MustCompile(`^\{\{-?\s*range\s+(.+?)\s*-?\}\}`)
+	rangeEnd   = regexp.MustCompile(`\{\{-?\s*end\s*-?\}\}$`)
+)
+
+// NewFromTemplate creates an Excel instance from the template workbook,
+// such as a branded workbook with a logo and a cover. The sheets, styles
+// and defined names of the template are kept, and the placeholders are
+// filled with data by ExecuteTemplate.
+//
+// The workbook is saved as book by SaveAndClose; the template itself is
+// not modified. NewSheet adds sheets after the sheets of the template,
+// and the default font of the template is kept.
+//
+// Example:
+//
+//	e, err := NewFromTemplate("template.xlsx", "out.xlsx",
+//		map[string]string{"Title": "設計書"})
+func NewFromTemplate(tmpl, book string, data any) (*Excel, error) {
+	f, err := excelize.OpenFile(tmpl)
+	if err != nil {
+		return nil, fmt.Errorf("cannot open template: %s, %w", tmpl, err)
+	}
+	e := &Excel{
+		f:            f,
+		book:         book,
+		Col:          1,
+		Row:          1,
+		fontSize:     defaultFontSize,
+		cellStyleIDs: make(map[cellStyle]int),
+		fromTemplate: true,
+	}
+	// テンプレートのデフォルトのフォント サイズ
+	if style, err := f.GetStyle(0); err == nil && style.Font != nil &&
+		style.Font.Size > 0 {
+		e.fontSize = style.Font.Size
+	}
+	if err := e.ExecuteTemplate(data); err != nil {
+		_ = f.Close()
+		return nil, fmt.Errorf("NewFromTemplate: %s: %w", tmpl, err)
+	}
+	return e, nil
+}
+
+// templateRegion is the rows repeated for each item of a range.
+type templateRegion struct {
+	pipeline   string // range の対象 (例: .Policies)
+	row1, row2 int    // 繰り返す行の範囲
+	col1, col2 int    // {{range}} のセルと {{end}} のセルの列
+}
+
+// ExecuteTemplate fills the placeholders of every sheet with data by
+// text/template, so that the layout of a report can be changed in Excel.
+//
+// A cell containing placeholders such as {{.Title}} is executed with
+// data, and a result such as "42" is set as a number. A text which is not
+// a valid template is left as it is. The rows from a cell starting with
+// {{range .Policies}} to a cell ending with {{end}} are repeated once per
+// item of the slice, keeping their styles; in the rows, the dot is the
+// item and $ is data. The pipeline of range is evaluated once. The rows,
+// merged ranges and borders below are shifted. Formulas are not changed.
+//
+// Example:
+//
+//	e, err := OpenExcel("report-template.xlsx")
+//	...
+//	err = e.ExecuteTemplate(data)
+//	...
+//	err = e.GetFile().SaveAs("report.xlsx")
+func (e *Excel) ExecuteTemplate(data any) error {
+	for _, sheet := range e.f.GetSheetList() {
+		if err := e.executeSheetTemplate(sheet, data); err != nil {
+			return fmt.Errorf("ExecuteTemplate: %w", err)
+		}
+	}
+	return nil
+}
+
+// executeSheetTemplate fills the placeholders of the sheet.
+func (e *Excel) executeSheetTemplate(sheet string, data any) error {
+	rows, err := e.f.GetRows(sheet, excelize.Options{RawCellValue: true})
+	if err != nil {
+		return err
+	}
+	regions, err := findRegions(sheet, rows)
+	if err != nil {
+		return err
+	}
+
+	// 繰り返す範囲の外のセル
+	inRegion := func(row int) bool {
+		for _, r := range regions {
+			if r.row1 <= row && row <= r.row2 {
+				return true
+			}
+		}
+		return false
+	}
+	for r, row := range rows {
+		if inRegion(r + 1) {
+			continue
+		}
+		for c, value := range row {
+			cell, err := excelize.CoordinatesToCellName(c+1, r+1)
+			if err != nil {
+				return err
+			}
+			if err := e.executeCell(sheet, c+1, r+1,
+				parsePlaceholder(sheet+"!"+cell, value), value, "",
+				data); err != nil {
+				return err
+			}
+		}
+	}
+
+	// 下の範囲から展開して、上の範囲の行番号を変えない
+	for i := len(regions) - 1; i >= 0; i-- {
+		if err := e.expandRegion(sheet, rows, regions[i], data); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// findRegions finds the rows from {{range}} to {{end}}. A cell which is a
+// complete template by itself, such as {{range .A}}{{.}}{{end}}, is not a
+// region.
+func findRegions(sheet string, rows [][]string) ([]templateRegion, error) {
+	var (
+		regions []templateRegion
+		current *templateRegion
+	)
+	for r, row := range rows {
+		for c, value := range row {
+			if !strings.Contains(value, "{{") || isCompleteTemplate(value) {
+				continue
+			}
+			cell, _ := excelize.CoordinatesToCellName(c+1, r+1)
+			if current == nil {
+				m := rangeBegin.FindStringSubmatch(value)
+				if m == nil {
+					continue
+				}
+				current = &templateRegion{pipeline: m[1], row1: r + 1,
+					col1: c + 1}
+				value = rangeBegin.ReplaceAllString(value, "")
+			} else if rangeBegin.MatchString(value) {
+				return nil, fmt.Errorf("%s!%s: nested range is not supported",
+					sheet, cell)
+			}
+			if rangeEnd.MatchString(value) {
+				current.row2, current.col2 = r+1, c+1
+				regions = append(regions, *current)
+				current = nil
+			}
+		}
+	}
+	if current != nil {
+		return nil, fmt.Errorf("%s: {{range %s}} at row %d has no {{end}}",
+			sheet, current.pipeline, current.row1)
+	}
+	return regions, nil
+}
+
+// isCompleteTemplate reports whether the text can be parsed by itself.
+func isCompleteTemplate(text string) bool {
+	_, err := template.New("").Parse(text)
+	return err == nil
+}
+
+// expandRegion repeats the rows of the region for each item, and fills
+// the placeholders of the rows with the item.
+func (e *Excel) expandRegion(sheet string, rows [][]string,
+	region templateRegion, data any) error {
+	items, err := rangeValue(region.pipeline, data)
+	if err != nil {
+		return fmt.Errorf("%s: row %d: %w", sheet, region.row1, err)
+	}
+	n := 0
+	if items.IsValid() {
+		n = items.Len()
+	}
+	height := region.row2 - region.row1 + 1
+	if n == 0 {
+		for row := region.row2; row >= region.row1; row-- {
+			if err := e.f.RemoveRow(sheet, row); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	// 複数行にまたがる結合範囲は DuplicateRowTo で複製されないため、別に結合する
+	merged, err := e.f.GetMergeCells(sheet)
+	if err != nil {
+		return err
+	}
+	var blockMerges [][4]int
+	for _, m := range merged {
+		col1, row1, col2, row2, err := rangeToCoordinates(
+			m.GetStartAxis() + ":" + m.GetEndAxis())
+		if err != nil {
+			return err
+		}
+		if row1 != row2 && region.row1 <= row1 && row2 <= region.row2 {
+			blockMerges = append(blockMerges, [4]int{col1, row1, col2, row2})
+		}
+	}
+
+	for k := 1; k < n; k++ {
+		for j := range height {
+			if err := e.f.DuplicateRowTo(sheet, region.row1+j,
+				region.row1+k*height+j); err != nil {
+				return err
+			}
+		}
+		for _, m := range blockMerges {
+			cell1, _ := excelize.CoordinatesToCellName(m[0], m[1]+k*height)
+			cell2, _ := excelize.CoordinatesToCellName(m[2], m[3]+k*height)
+			if err := e.f.MergeCell(sheet, cell1, cell2); err != nil {
+				return err
+			}
+		}
+	}
+
+	// 各セルのテンプレートは一度だけ解析し、k 番目の要素をドットとして実行する
+	var item []any
+	current := func() []any { return item }
+	type regionCell struct {
+		col, row       int // 範囲の先頭の行での位置
+		text, original string
+		tmpl           *template.Template
+	}
+	var cells []regionCell
+	for j := range height {
+		if region.row1+j > len(rows) {
+			break
+		}
+		for c, value := range rows[region.row1-1+j] {
+			original := value
+			if j == 0 && c+1 == region.col1 {
+				value = rangeBegin.ReplaceAllString(value, "")
+			}
+			if j == height-1 && c+1 == region.col2 {
+				value = rangeEnd.ReplaceAllString(value, "")
+			}
+			cell, err := excelize.CoordinatesToCellName(c+1, region.row1+j)
+			if err != nil {
+				return err
+			}
+			cells = append(cells, regionCell{col: c + 1, row: region.row1 + j,
+				text: value, original: original,
+				tmpl: parseItemPlaceholder(sheet+"!"+cell, value, current)})
+		}
+	}
+	for k := range n {
+		item = []any{items.Index(k).Interface()}
+		for _, c := range cells {
+			if err := e.executeCell(sheet, c.col, c.row+k*height, c.tmpl,
+				c.text, c.original, data); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// executeCell executes the template of the cell, and sets the result. The
+// cell is not changed if the text has no placeholders and is the same as
+// original, or the cell has a formula. If t is nil, such as for a literal
+// "{{", the text is set as it is. A result which is a number is set as a
+// number.
+func (e *Excel) executeCell(sheet string, col, row int, t *template.Template,
+	text, original string, data any) error {
+	if t == nil && (original == "" || text == original) {
+		return nil
+	}
+	cell, err := excelize.CoordinatesToCellName(col, row)
+	if err != nil {
+		return err
+	}
+	formula, err := e.f.GetCellFormula(sheet, cell)
+	if err != nil {
+		return err
+	}
+	if formula != "" {
+		return nil
+	}
+	if t == nil {
+		return e.f.SetCellStr(sheet, cell, text)
+	}
+	var sb strings.Builder
+	if err := t.Execute(&sb, data); err != nil {
+		return err
+	}
+	return e.f.SetCellValue(sheet, cell, numericValue(sb.String()))
+}
+
+// parsePlaceholder parses the text of a cell as text/template, where
+// missing keys of maps are errors. It returns nil if the text has no
+// placeholders or is not a template.
+func parsePlaceholder(name, text string) *template.Template {
+	if !strings.Contains(text, "{{") {
+		return nil
+	}
+	t, err := template.New(name).Option("missingkey=error").
+		Parse(quoteRefs(text))
+	if err != nil {
+		// テンプレートでない "{{" はそのまま残す
+		return nil
+	}
+	return t
+}
+
+// quoteRefs quotes the cross-references such as {{ref:tbl-policies}} in
+// the text, so that the template outputs them as they are for
+// SaveAndClose.
+func quoteRefs(text string) string {
+	return captionRef.ReplaceAllStringFunc(text, func(ref string) string {
+		return "{{" + strconv.Quote(ref) + "}}"
+	})
+}
+
+// parseItemPlaceholder parses the text of a cell in a range region. The
+// dot is the item returned by item, which is a slice of the item, and $ is
+// the data as outside the region.
+func parseItemPlaceholder(name, text string,
+	item func() []any) *template.Template {
+	if parsePlaceholder(name, text) == nil {
+		return nil
+	}
+	t, err := template.New(name).Option("missingkey=error").
+		Funcs(template.FuncMap{"tomatoItem": item}).
+		Parse("{{range tomatoItem}}" + quoteRefs(text) + "{{end}}")
+	if err != nil {
+		return nil
+	}
+	return t
+}
+
+// rangeValue evaluates the pipeline of range once, and returns the items.
+// The value is invalid if the pipeline is nil. Only slices and arrays are
+// supported.
+func rangeValue(pipeline string, data any) (reflect.Value, error) {
+	var value any
+	t, err := template.New(pipeline).Option("missingkey=error").
+		Funcs(template.FuncMap{"tomatoCapture": func(v any) string {
+			value = v
+			return ""
+		}}).Parse("{{" + pipeline + " | tomatoCapture}}")
+	if err != nil {
+		return reflect.Value{}, err
+	}
+	if err := t.Execute(&strings.Builder{}, data); err != nil {
+		return reflect.Value{}, err
+	}
+	if value == nil {
+		return reflect.Value{}, nil
+	}
+	v := reflect.ValueOf(value)
+	switch v.Kind() {
+	case reflect.Slice, reflect.Array:
+		return v, nil
+	}
+	return reflect.Value{}, fmt.Errorf("range over %s is not supported, want a slice: %s",
+		v.Type(), pipeline)
+}
@@ -3,9 +3,11 @@ package excel
 import (
 	"errors"
 	"fmt"
+	"math"
 	"strconv"
 	"strings"
 
+	"github.com/mattn/go-runewidth"
 	"github.com/nonsugar-go/tools/excel/dataframe"
 	"github.com/xuri/excelize/v2"
 )
@@ -54,7 +56,7 @@ func (e *Excel) pageSetting(sheetType SheetType, title string) error {
 		}
 	case SheetTypeTOC:
 		e.Col, e.Row = 1, 1
-		title = "目次"
+		title = tocSheet
 		if err := e.f.SetCellStr(e.sheet, "A1", title); err != nil {
 			return fmt.Errorf(
 				"failed to set value for cell 'A1' on sheet '%s': %s: %w",
@@ -114,11 +116,7 @@ func (e *Excel) pageSetting(sheetType SheetType, title string) error {
 		e.DrawBorders("A3", maxRightCell+"3", BorderContinuousWeight3)
 
 		// 印刷タイトル - タイトル行: $1:$3
-		if err := e.f.SetDefinedName(&excelize.DefinedName{
-			Name:     "_xlnm.Print_Titles",
-			RefersTo: fmt.Sprintf("'%s'!$1:$3", e.sheet),
-			Scope:    e.sheet,
-		}); err != nil {
+		if err := e.f.SetDefinedName(tomatoPrintTitles(e.sheet)); err != nil {
 			return fmt.Errorf("failed to set print titles on sheet '%s': %w",
 				e.sheet, err)
 		}
@@ -126,18 +124,7 @@ func (e *Excel) pageSetting(sheetType SheetType, title string) error {
 	}
 
 	// ヘッダーとフッターの設定
-	if err := e.f.SetHeaderFooter(e.sheet, &excelize.HeaderFooterOptions{
-		AlignWithMargins: (*bool)(nil),
-		DifferentFirst:   false,
-		DifferentOddEven: false,
-		EvenFooter:       "",
-		EvenHeader:       "",
-		FirstFooter:      "",
-		FirstHeader:      "",
-		OddFooter:        "&C&P / &N",
-		OddHeader:        "",
-		ScaleWithDoc:     (*bool)(nil),
-	}); err != nil {
+	if err := e.f.SetHeaderFooter(e.sheet, tomatoHeaderFooter()); err != nil {
 		return fmt.Errorf("failed to set header and footer on sheet '%s': %w",
 			e.sheet, err)
 	}
@@ -150,50 +137,13 @@ func (e *Excel) pageSetting(sheetType SheetType, title string) error {
 		// 印刷向きが縦の場合
 
 		// ページレイアウトの設定
-		var (
-			adjustTo        uint = 100          // 拡大率=100%
-			blackAndWhite        = false        // 白黒印刷しない
-			firstPageNumber      = (*uint)(nil) // 先頭ページ番号=自動設定
-			fitToHeight          = (*int)(nil)
-			fitToWidth           = (*int)(nil)
-			orientation          = "portrait" // 印刷の向き=縦
-			size                 = 9          // 用紙サイズ=A4 (210 mm × 297 mm)
-		)
-		if err := e.f.SetPageLayout(e.sheet, &excelize.PageLayoutOptions{
-			AdjustTo:        &adjustTo,
-			BlackAndWhite:   &blackAndWhite,
-			FirstPageNumber: firstPageNumber,
-			FitToHeight:     fitToHeight,
-			FitToWidth:      fitToWidth,
-			Orientation:     &orientation,
-			Size:            &size,
-		}); err != nil {
+		if err := e.f.SetPageLayout(e.sheet, tomatoPageLayout()); err != nil {
 			return fmt.Errorf("failed to page layout on sheet '%s': %w",
 				e.sheet, err)
 		}
 
 		// 印刷マージンの設定
-		var (
-			bottom       = 0.629921269229078
-			footer       = 0.2362204818275031
-			header       = 0.2362204818275031
-			horizontally = (*bool)(nil)
-			left         = 0.629921269229078
-			right        = 0.2362204818275031
-			top          = 0.629921269229078
-			vertically   = (*bool)(nil)
-		)
-		if err := e.f.SetPageMargins(e.sheet,
-			&excelize.PageLayoutMarginsOptions{
-				Bottom:       &bottom,
-				Footer:       &footer,
-				Header:       &header,
-				Horizontally: horizontally,
-				Left:         &left,
-				Right:        &right,
-				Top:          &top,
-				Vertically:   vertically,
-			}); err != nil {
+		if err := e.f.SetPageMargins(e.sheet, tomatoPageMargins()); err != nil {
 			return fmt.Errorf("failed to page layout margins on sheet '%s': %w",
 				e.sheet, err)
 		}
@@ -209,6 +159,77 @@ func (e *Excel) pageSetting(sheetType SheetType, title string) error {
 	return nil
 }
 
+// tomatoPageLayout returns the page layout of the portrait sheets.
+func tomatoPageLayout() *excelize.PageLayoutOptions {
+	var (
+		adjustTo        uint = 100          // 拡大率=100%
+		blackAndWhite        = false        // 白黒印刷しない
+		firstPageNumber      = (*uint)(nil) // 先頭ページ番号=自動設定
+		fitToHeight          = (*int)(nil)
+		fitToWidth           = (*int)(nil)
+		orientation          = "portrait" // 印刷の向き=縦
+		size                 = 9          // 用紙サイズ=A4 (210 mm × 297 mm)
+	)
+	return &excelize.PageLayoutOptions{
+		AdjustTo:        &adjustTo,
+		BlackAndWhite:   &blackAndWhite,
+		FirstPageNumber: firstPageNumber,
+		FitToHeight:     fitToHeight,
+		FitToWidth:      fitToWidth,
+		Orientation:     &orientation,
+		Size:            &size,
+	}
+}
+
+// tomatoPageMargins returns the page margins of the portrait sheets.
+func tomatoPageMargins() *excelize.PageLayoutMarginsOptions {
+	var (
+		bottom       = 0.629921269229078
+		footer       = 0.2362204818275031
+		header       = 0.2362204818275031
+		horizontally = (*bool)(nil)
+		left         = 0.629921269229078
+		right        = 0.2362204818275031
+		top          = 0.629921269229078
+		vertically   = (*bool)(nil)
+	)
+	return &excelize.PageLayoutMarginsOptions{
+		Bottom:       &bottom,
+		Footer:       &footer,
+		Header:       &header,
+		Horizontally: horizontally,
+		Left:         &left,
+		Right:        &right,
+		Top:          &top,
+		Vertically:   vertically,
+	}
+}
+
+// tomatoHeaderFooter returns the header and footer with the page number.
+func tomatoHeaderFooter() *excelize.HeaderFooterOptions {
+	return &excelize.HeaderFooterOptions{
+		AlignWithMargins: (*bool)(nil),
+		DifferentFirst:   false,
+		DifferentOddEven: false,
+		EvenFooter:       "",
+		EvenHeader:       "",
+		FirstFooter:      "",
+		FirstHeader:      "",
+		OddFooter:        "&C" + pageFormat,
+		OddHeader:        "",
+		ScaleWithDoc:     (*bool)(nil),
+	}
+}
+
+// tomatoPrintTitles returns the print titles of the sheet: $1:$3.
+func tomatoPrintTitles(sheet string) *excelize.DefinedName {
+	return &excelize.DefinedName{
+		Name:     printTitlesName,
+		RefersTo: fmt.Sprintf("'%s'!$1:$3", sheet),
+		Scope:    sheet,
+	}
+}
+
 type TomatoBorderType int
 
 const (
@@ -967,7 +988,9 @@ func (e *Excel) MakeTOC() error {
 		number  [maxHeaderLevel + 1]int // ヘッダレベルごとの番号を保持
 		headers []headersInfo           // ヘッダの情報
 	)
-	for _, sheet := range e.f.GetSheetList() {
+	sheets := e.f.GetSheetList()
+	for i, sheet := range sheets {
+		e.reportProgress(i+1, len(sheets), "MakeTOC: "+sheet)
 		comments, err := e.GetSortedComments(sheet)
 		if err != nil {
 			return fmt.Errorf(
@@ -1147,7 +1170,21 @@ func (e *Excel) MarkHeader(headerLevel int) error {
 			return fmt.Errorf("failed to delete header mark: %w", err)
 		}
 	} else {
-		// ヘッダの印を付ける
+		// ヘッダの印を付ける (既にヘッダの印がある場合は付け直す)
+		exists, marked, err := e.commentAt(cell)
+		if err != nil {
+			return fmt.Errorf("failed to set header mark: %w", err)
+		}
+		if exists && !marked {
+			// ヘッダの印でないコメントは残す
+			return fmt.Errorf(
+				"failed to set header mark: cell %s already has a comment", cell)
+		}
+		if marked {
+			if err := e.f.DeleteComment(e.sheet, cell); err != nil {
+				return fmt.Errorf("failed to set header mark: %w", err)
+			}
+		}
 		if err := e.SetStyle(NewStyle(fontBold)); err != nil {
 			return fmt.Errorf("failed to set header mark: %w", err)
 		}
@@ -1159,6 +1196,77 @@ func (e *Excel) MarkHeader(headerLevel int) error {
 	return nil
 }
 
+// commentAt reports whether the cell has a comment, and whether the comment
+// is a header mark.
+func (e *Excel) commentAt(cell string) (exists, marked bool, err error) {
+	comments, err := e.f.GetComments(e.sheet)
+	if err != nil {
+		return false, false, err
+	}
+	for _, comment := range comments {
+		if comment.Cell != cell {
+			continue
+		}
+		marked = strings.HasPrefix(comment.Text, headerMark)
+		for _, paragraph := range comment.Paragraph {
+			if strings.HasPrefix(paragraph.Text, headerMark) {
+				marked = true
+			}
+		}
+		return true, marked, nil
+	}
+	return false, false, nil
+}
+
+// HeaderMark is a header marked by MarkHeader.
+type HeaderMark struct {
+	Cell  string // ヘッダのセル座標
+	Level int    // ヘッダのレベル
+	Value string // ヘッダのセルの値
+}
+
+// GetHeaderMarks returns the headers marked by MarkHeader in the sheet,
+// sorted by row and column.
+func (e *Excel) GetHeaderMarks(sheet string) ([]HeaderMark, error) {
+	comments, err := e.GetSortedComments(sheet)
+	if err != nil {
+		return nil, err
+	}
+	var marks []HeaderMark
+	for _, comment := range comments {
+		for _, paragraph := range comment.Paragraph {
+			text := paragraph.Text
+			if !strings.HasPrefix(text, headerMark) {
+				continue
+			}
+			level, err := headerLevel(text)
+			if err != nil {
+				return nil, fmt.Errorf("%w at cell '%s' in sheet '%s'",
+					err, comment.Cell, sheet)
+			}
+			value, err := e.f.GetCellValue(sheet, comment.Cell)
+			if err != nil {
+				return nil, fmt.Errorf(
+					"failed to get cell value at %s: %w", comment.Cell, err)
+			}
+			marks = append(marks,
+				HeaderMark{Cell: comment.Cell, Level: level, Value: value})
+			break
+		}
+	}
+	return marks, nil
+}
+
+// headerLevel returns the level of the header mark such as
+// "TOMATO: Header1".
+func headerLevel(text string) (int, error) {
+	level, err := strconv.Atoi(strings.TrimPrefix(text, headerMark))
+	if err != nil || level < 1 || level > maxHeaderLevel {
+		return 0, fmt.Errorf("invalid header mark '%s'", text)
+	}
+	return level, nil
+}
+
 // h1H2H3 is a helper function used by the H1, H2, and H3 functions.
 func (e *Excel) h1H2H3(title string, level int) error {
 	cell, err := excelize.CoordinatesToCellName(e.Col, e.Row)
@@ -1306,8 +1414,160 @@ func (e *Excel) WriteCodeBlock(lines []string) error {
 	return nil
 }
 
+// HAlign is the horizontal alignment of a column written by WriteDF.
+type HAlign int
+
+const (
+	HAlignGeneral HAlign = iota // 標準
+	HAlignLeft                  // 左詰め
+	HAlignCenter                // 中央揃え
+	HAlignRight                 // 右詰め
+)
+
+// style returns the alignment style of the HAlign.
+func (a HAlign) style() (cellStyle, error) {
+	switch a {
+	case HAlignGeneral:
+		return styleNormal, nil
+	case HAlignLeft:
+		return alignmentHorizontalLeft, nil
+	case HAlignCenter:
+		return alignmentHorizontalCenter, nil
+	case HAlignRight:
+		return alignmentHorizontalRight, nil
+	}
+	return styleNormal, fmt.Errorf("invalid alignment: %v", a)
+}
+
+// WriteDFOptions specifies how WriteDFWithOptions lays out a DataFrame.
+// The zero value is the layout of WriteDF.
+type WriteDFOptions struct {
+	// BorderType is the type of the table. Default is TBorderHHeader,
+	// or TBorderHHeaderG if the DataFrame has IsArray columns.
+	BorderType TomatoBorderType
+	// StartCol is the column where the table starts. The columns of the
+	// headers are shifted to start at StartCol. Default is the column of
+	// the first header, which does not shift them.
+	StartCol int
+	// EndCol is the right edge of the table.
+	// Default is maxRightCellNumber ("AG").
+	EndCol int
+	// Align is the horizontal alignment of the values of each column,
+	// in the order of the headers.
+	Align []HAlign
+	// Wrap wraps the values instead of shrinking them to fit.
+	Wrap bool
+	// HeaderFillColor is the RGB color code of the header cells, such as
+	// "C0C0C0". It must be a color of the package palette.
+	// Default is the header color of the TOMATO macro.
+	HeaderFillColor string
+	// AutoFit computes the span of each column from the display width of
+	// its name and values. Header.Width takes precedence when set.
+	// The last column is stretched to EndCol, and the columns are narrowed
+	// so that the table does not go past EndCol.
+	AutoFit bool
+
+	// TableName writes the DataFrame as an Excel table with this name
+	// instead of TOMATO borders. Each header occupies a single column from
+	// StartCol, and AutoFit sets the column widths. Align, Wrap and
+	// HeaderFillColor are applied to the cells, while BorderType and
+	// EndCol are not used.
+	TableName string
+	// Table specifies the Excel table. The range is set by WriteDF, and
+	// the name and the options are checked as AddTable before writing.
+	Table TableOptions
+}
+
+// headerCol returns the column number of the header.
+func headerCol(h dataframe.Header) (int, error) {
+	if h.Col != 0 {
+		return h.Col, nil
+	}
+	col, err := excelize.ColumnNameToNumber(h.ColumnName)
+	if err != nil {
+		return 0, fmt.Errorf("invalid column of header '%s': %w", h.Name, err)
+	}
+	return col, nil
+}
+
+// dfStartCol returns the column where the table starts, which is
+// opts.StartCol or the column of the first header.
+func dfStartCol(df *dataframe.DataFrame, opts WriteDFOptions) (int, error) {
+	if opts.StartCol != 0 {
+		return opts.StartCol, nil
+	}
+	return headerCol(df.Headers[0])
+}
+
+// dfColumns returns the start column of each header and the right edge of
+// the table.
+func dfColumns(df *dataframe.DataFrame, opts WriteDFOptions) (
+	[]int, int, error) {
+	start, err := dfStartCol(df, opts)
+	if err != nil {
+		return nil, 0, err
+	}
+	end := opts.EndCol
+	if end == 0 {
+		end = maxRightCellNumber
+	}
+	cols := make([]int, len(df.Headers))
+	if opts.AutoFit {
+		if start+len(cols)-1 > end {
+			return nil, 0, fmt.Errorf(
+				"%d columns from column %d do not fit up to column %d",
+				len(cols), start, end)
+		}
+		next := start
+		for i, h := range df.Headers {
+			cols[i] = next
+			span := h.Width
+			if span <= 0 {
+				width := runewidth.StringWidth(h.Name)
+				for _, record := range df.Records {
+					if i < len(record) {
+						width = max(width, runewidth.StringWidth(record[i]))
+					}
+				}
+				// セルの幅 (defaultColWidth) 単位に切り上げる
+				span = max(1, int(math.Ceil(
+					(float64(width)+1.7)/defaultColWidth)))
+			}
+			next += span
+		}
+		// EndCol を超える場合は、各列に1セル以上を残して右から詰める
+		for i := range cols {
+			cols[i] = min(cols[i], end-(len(cols)-1-i))
+		}
+		return cols, end, nil
+	}
+	shift := 0
+	for i, h := range df.Headers {
+		col, err := headerCol(h)
+		if err != nil {
+			return nil, 0, err
+		}
+		if i == 0 {
+			shift = start - col
+		}
+		cols[i] = col + shift
+		if i > 0 && cols[i] <= cols[i-1] {
+			return nil, 0, fmt.Errorf(
+				"invalid column of header '%s': columns must be in ascending order",
+				h.Name)
+		}
+	}
+	if cols[0] < 1 || cols[len(cols)-1] > end {
+		return nil, 0, fmt.Errorf(
+			"the columns of the headers must be between %d and %d",
+			1, end)
+	}
+	return cols, end, nil
+}
+
 // WriteDF writes DataFrame.
-// Default border type is TBorderHHeader.
+// Default border type is TBorderHHeader, or TBorderHHeaderG if the
+// DataFrame has IsArray columns.
 //
 // Example:
 //
@@ -1316,12 +1576,40 @@ func (e *Excel) WriteCodeBlock(lines []string) error {
 //	err := e.WriteDF(df, TBorderVHeader)
 func (e *Excel) WriteDF(df *dataframe.DataFrame,
 	borderType ...TomatoBorderType) error {
+	var opts WriteDFOptions
+	if len(borderType) > 0 {
+		opts.BorderType = borderType[0]
+	}
+	return e.WriteDFWithOptions(df, opts)
+}
+
+// WriteDFWithOptions writes DataFrame with the specified layout.
+//
+// Example:
+//
+//	err := e.WriteDFWithOptions(df, WriteDFOptions{
+//		AutoFit: true,
+//		Align:   []HAlign{HAlignCenter, HAlignGeneral, HAlignRight},
+//		Wrap:    true,
+//	})
+func (e *Excel) WriteDFWithOptions(df *dataframe.DataFrame,
+	opts WriteDFOptions) error {
 	if df == nil {
 		return errors.New("invalid input: the provided DataFrame is nil")
 	}
-	bType := TBorderHHeader
-	if len(borderType) > 0 {
-		bType = borderType[0]
+	if len(df.Headers) == 0 {
+		return errors.New("invalid input: the DataFrame has no headers")
+	}
+	bType := opts.BorderType
+	if bType == TBorderUnknown {
+		bType = TBorderHHeader
+		// 配列形式の列があれば、グループ対応の表にする
+		for _, h := range df.Headers {
+			if h.IsArray {
+				bType = TBorderHHeaderG
+				break
+			}
+		}
 	}
 	switch bType {
 	case TBorderHHeader, TBorderHHeaderG, TBorderVHeader:
@@ -1329,23 +1617,282 @@ func (e *Excel) WriteDF(df *dataframe.DataFrame,
 	default:
 		return fmt.Errorf("invalid border type: %v", bType)
 	}
-	cell1, _ := e.CR(2).LF().Cell()
-	for _, h := range df.Headers {
-		c, _ := excelize.ColumnNameToNumber(h.ColumnName)
-		e.CR(c).SetVal(h.Name)
+	var headerFill cellStyle
+	if opts.HeaderFillColor != "" {
+		var err error
+		if headerFill, err = fillStyle(opts.HeaderFillColor); err != nil {
+			return fmt.Errorf("WriteDF: %w", err)
+		}
+	}
+	if opts.TableName != "" {
+		return e.writeDFTable(df, opts, headerFill)
+	}
+	cols, endCol, err := dfColumns(df, opts)
+	if err != nil {
+		return fmt.Errorf("WriteDF: %w", err)
+	}
+
+	col1, row1 := cols[0], e.Row+1
+	e.CR(col1).LF()
+	for i, h := range df.Headers {
+		if err := e.CR(cols[i]).SetVal(h.Name); err != nil {
+			return fmt.Errorf("WriteDF: %w", err)
+		}
 	}
-	for _, values := range df.Records {
+	for r, values := range df.Records {
+		e.reportProgress(r+1, len(df.Records), "WriteDF")
 		e.LF()
-		for i, h := range df.Headers {
-			e.CR(h.Col).SetVal(values[i])
-			i++
+		for i := range df.Headers {
+			if i >= len(values) {
+				break
+			}
+			if err := e.CR(cols[i]).SetVal(values[i]); err != nil {
+				return fmt.Errorf("WriteDF: %w", err)
+			}
 		}
 	}
-	e.Col = maxRightCellNumber
-	cell2, _ := e.Cell()
+	row2 := e.Row
+	e.Col = endCol
+	cell1, err := excelize.CoordinatesToCellName(col1, row1)
+	if err != nil {
+		return fmt.Errorf("WriteDF: %w", err)
+	}
+	cell2, err := e.Cell()
+	if err != nil {
+		return fmt.Errorf("WriteDF: %w", err)
+	}
 	if err := e.DrawBorders2(cell1, cell2, bType); err != nil {
 		return err
 	}
+	e.dfRange = cell1 + ":" + cell2
+
+	// 値を書き込む範囲 (垂直ヘッダの場合は、1列目を除く全ての行)
+	dataCol, dataRow := col1, row1+1
+	if bType == TBorderVHeader {
+		dataCol, dataRow = endCol+1, row1
+		if len(cols) > 1 {
+			dataCol = cols[1]
+		}
+	}
+
+	// ヘッダの色を変える
+	if headerFill != styleNormal {
+		c2, r2 := endCol, row1
+		if bType == TBorderVHeader {
+			c2, r2 = dataCol-1, row2
+		}
+		if err := e.setStyleForArea(col1, row1, c2, r2,
+			NewStyle(headerFill)); err != nil {
+			return fmt.Errorf("WriteDF: %w", err)
+		}
+	}
+
+	// 折り返して全体を表示する
+	if opts.Wrap && dataCol <= endCol && dataRow <= row2 {
+		if err := e.setStyleForArea(dataCol, dataRow, endCol, row2,
+			NewStyle(alignmentWrapText)); err != nil {
+			return fmt.Errorf("WriteDF: %w", err)
+		}
+	}
+
+	// 入力できる値の一覧
+	for i, h := range df.Headers {
+		if len(h.Allowed) == 0 || cols[i] < dataCol || dataRow > row2 {
+			continue
+		}
+		c2 := endCol
+		if i+1 < len(cols) {
+			c2 = cols[i+1] - 1
+		}
+		if err := e.addValidationListForArea(cols[i], dataRow, c2, row2,
+			h.Allowed); err != nil {
+			return fmt.Errorf("WriteDF: %w", err)
+		}
+	}
+
+	// 列ごとの横位置
+	for i, align := range opts.Align {
+		if i >= len(cols) {
+			break
+		}
+		style, err := align.style()
+		if err != nil {
+			return fmt.Errorf("WriteDF: %w", err)
+		}
+		if style == styleNormal {
+			continue
+		}
+		c2 := endCol
+		if i+1 < len(cols) {
+			c2 = cols[i+1] - 1
+		}
+		if cols[i] < dataCol || dataRow > row2 {
+			continue
+		}
+		if err := e.setStyleForArea(cols[i], dataRow, c2, row2,
+			NewStyle(style)); err != nil {
+			return fmt.Errorf("WriteDF: %w", err)
+		}
+	}
 
 	return nil
 }
+
+// writeDFTable writes DataFrame as an Excel table. The header cells are
+// filled with headerFill unless it is styleNormal.
+func (e *Excel) writeDFTable(df *dataframe.DataFrame,
+	opts WriteDFOptions, headerFill cellStyle) error {
+	// 書き込む前にオプションを確認する
+	aligns := make([]cellStyle, len(df.Headers))
+	for i, align := range opts.Align {
+		if i >= len(aligns) {
+			break
+		}
+		var err error
+		if aligns[i], err = align.style(); err != nil {
+			return fmt.Errorf("WriteDF: %w", err)
+		}
+	}
+
+	col1, err := dfStartCol(df, opts)
+	if err != nil {
+		return fmt.Errorf("WriteDF: %w", err)
+	}
+	row1 := e.Row + 1
+	// テーブルには、ヘッダの他に1行以上が必要
+	cell1, err := excelize.CoordinatesToCellName(col1, row1)
+	if err != nil {
+		return fmt.Errorf("WriteDF: %w", err)
+	}
+	cell2, err := excelize.CoordinatesToCellName(col1+len(df.Headers)-1,
+		row1+max(len(df.Records), 1))
+	if err != nil {
+		return fmt.Errorf("WriteDF: %w", err)
+	}
+	table := opts.Table
+	table.Range = cell1 + ":" + cell2
+	if err := e.checkTable(opts.TableName, table); err != nil {
+		return fmt.Errorf("WriteDF: %w", err)
+	}
+
+	e.CR(col1).LF()
+	for i, h := range df.Headers {
+		if err := e.CR(col1 + i).SetVal(h.Name); err != nil {
+			return fmt.Errorf("WriteDF: %w", err)
+		}
+	}
+	for r, values := range df.Records {
+		e.reportProgress(r+1, len(df.Records), "WriteDF")
+		e.LF()
+		for i := range df.Headers {
+			if i >= len(values) {
+				break
+			}
+			// 集計できるよう、数値はそのまま数値として書き込む
+			if err := e.CR(col1 + i).SetVal(numericValue(values[i])); err != nil {
+				return fmt.Errorf("WriteDF: %w", err)
+			}
+		}
+	}
+	if opts.AutoFit {
+		for i, h := range df.Headers {
+			width := runewidth.StringWidth(h.Name)
+			for _, record := range df.Records {
+				if i < len(record) {
+					width = max(width, runewidth.StringWidth(record[i]))
+				}
+			}
+			colName, err := excelize.ColumnNumberToName(col1 + i)
+			if err != nil {
+				return fmt.Errorf("WriteDF: %w", err)
+			}
+			// フィルタのボタンの分だけ広げる
+			if err := e.f.SetColWidth(e.sheet, colName, colName,
+				float64(width)+3.7); err != nil {
+				return fmt.Errorf("WriteDF: %w", err)
+			}
+		}
+	}
+	if len(df.Records) == 0 {
+		e.LF()
+	}
+	e.Col = col1 + len(df.Headers) - 1
+	e.dfRange = table.Range
+	col2, row2 := e.Col, e.Row
+	if headerFill != styleNormal {
+		if err := e.setStyleForArea(col1, row1, col2, row1,
+			NewStyle(headerFill)); err != nil {
+			return fmt.Errorf("WriteDF: %w", err)
+		}
+	}
+	for i, style := range aligns {
+		if opts.Wrap {
+			style |= alignmentWrapText
+		}
+		if style == styleNormal {
+			continue
+		}
+		if err := e.setStyleForArea(col1+i, row1+1, col1+i, row2,
+			NewStyle(style)); err != nil {
+			return fmt.Errorf("WriteDF: %w", err)
+		}
+	}
+	for i, h := range df.Headers {
+		if len(h.Allowed) == 0 {
+			continue
+		}
+		if err := e.addValidationListForArea(col1+i, row1+1, col1+i, e.Row,
+			h.Allowed); err != nil {
+			return fmt.Errorf("WriteDF: %w", err)
+		}
+	}
+	if err := e.AddTable(opts.TableName, table); err != nil {
+		return fmt.Errorf("WriteDF: %w", err)
+	}
+	if len(table.Subtotals) > 0 {
+		e.LF()
+	}
+	return nil
+}
+
+// numericValue returns the number of the text if the text is written as
+// the number, such as "88.5", and the text otherwise, such as "0003".
+// "NaN" and "Inf" are kept as the text, since Excel cannot store them as
+// numbers.
+func numericValue(text string) any {
+	if f, err := strconv.ParseFloat(text, 64); err == nil &&
+		!math.IsNaN(f) && !math.IsInf(f, 0) &&
+		strconv.FormatFloat(f, 'f', -1, 64) == text {
+		return f
+	}
+	return text
+}
+
+// addValidationListForArea adds a drop-down list to the cells between the
+// coordinates.
+func (e *Excel) addValidationListForArea(col1, row1, col2, row2 int,
+	values []string) error {
+	cell1, err := excelize.CoordinatesToCellName(col1, row1)
+	if err != nil {
+		return err
+	}
+	cell2, err := excelize.CoordinatesToCellName(col2, row2)
+	if err != nil {
+		return err
+	}
+	return e.AddValidationList(cell1+":"+cell2, values)
+}
+
+// setStyleForArea applies a style to the cells between the coordinates.
+func (e *Excel) setStyleForArea(col1, row1, col2, row2 int,
+	style cellStyle) error {
+	cell1, err := excelize.CoordinatesToCellName(col1, row1)
+	if err != nil {
+		return err
+	}
+	cell2, err := excelize.CoordinatesToCellName(col2, row2)
+	if err != nil {
+		return err
+	}
+	return e.SetStyleForCellRange(cell1, cell2, style)
+}
@@ -61,6 +61,7 @@ const (
 	levelColor9          = 15
 	defaultTextColumns   = 80
 	headerMark           = "TOMATO: Header"
+	printTitlesName      = "_xlnm.Print_Titles"
 	maxHeaderLevel       = 3
 	maxExcelRow          = 65536
 	maxExcelColumn       = 256
@@ -68,6 +69,8 @@ const (
 	preHeaderName        = "■"
 	sufHeaderName        = "_TOMATO"
 	tocName              = "■0._目次_TOMATO"
+	tocSheet             = "目次" // 目次のシート名
+	coverSheet           = "表紙" // 表紙のシート名
 	beginTableOfContents = "TOMATO: Begin{Table of Contents}"
 	endTableOfContents   = "TOMATO: End{Table of Contents}"
 	beginTextFile        = "TOMATO: Begin{Text File}"
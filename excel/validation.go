@@ -0,0 +1,126 @@
+package excel
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/xuri/excelize/v2"
+)
+
+// formulaEscaper escapes a formula of data validation, which is stored
+// as XML.
+var formulaEscaper = strings.NewReplacer(
+	`&`, `&amp;`,
+	`<`, `&lt;`,
+	`>`, `&gt;`,
+)
+
+// AddValidationList adds a drop-down list of the values to the cells in
+// the range. The range may be a list of ranges separated by spaces.
+//
+// Example:
+//
+//	err := e.AddValidationList("Q5:T20", []string{"enable", "disable"})
+func (e *Excel) AddValidationList(rangeRef string, values []string) error {
+	if len(values) == 0 {
+		return errors.New("AddValidationList: no values")
+	}
+	dv := excelize.NewDataValidation(true)
+	dv.SetSqref(rangeRef)
+	if err := dv.SetDropList(values); err != nil {
+		return fmt.Errorf("AddValidationList: %w", err)
+	}
+	dv.SetError(excelize.DataValidationErrorStyleStop,
+		"入力エラー", "一覧から値を選択してください。")
+	if err := e.f.AddDataValidation(e.sheet, dv); err != nil {
+		return fmt.Errorf("AddValidationList: %s: %w", rangeRef, err)
+	}
+	return nil
+}
+
+// AddValidationCustom allows only the values for which the formula returns
+// TRUE in the cells in the range. The references in the formula are
+// relative to the top-left cell of the range.
+//
+// Example:
+//
+//	err := e.AddValidationCustom("E5:H20", ValidIPv4Formula("E5"))
+func (e *Excel) AddValidationCustom(rangeRef, formula string) error {
+	formula = strings.TrimPrefix(formula, "=")
+	if formula == "" {
+		return errors.New("AddValidationCustom: no formula")
+	}
+	dv := excelize.NewDataValidation(true)
+	dv.SetSqref(rangeRef)
+	dv.Type = "custom"
+	dv.Formula1 = formulaEscaper.Replace(formula)
+	dv.SetError(excelize.DataValidationErrorStyleStop,
+		"入力エラー", "入力された値の形式が正しくありません。")
+	if err := e.f.AddDataValidation(e.sheet, dv); err != nil {
+		return fmt.Errorf("AddValidationCustom: %s: %w", rangeRef, err)
+	}
+	return nil
+}
+
+// ValidIPv4Formula returns a formula of AddValidationCustom which checks
+// that the cell is an IPv4 address: four groups of digits separated by dots.
+// The range of each octet is not checked.
+func ValidIPv4Formula(cell string) string {
+	return fmt.Sprintf(`AND(LEN(%[1]s)-LEN(SUBSTITUTE(%[1]s,".",""))=3,`+
+		`ISNUMBER(-SUBSTITUTE(%[1]s,".","")),ISERROR(FIND("..",%[1]s)),`+
+		`LEFT(%[1]s)<>".",RIGHT(%[1]s)<>".")`, cell)
+}
+
+// HighlightWhen highlights the cells in the range with the style where the
+// rule returns TRUE. The rule is a formula whose references are relative
+// to the top-left cell of the range. Only the font color, the bold and
+// the fill of the style are used.
+//
+// Example:
+//
+//	// 動作が deny の行を強調する
+//	err := e.HighlightWhen("B5:AG20", `$Q5="deny"`, NewStyle().Fill("FF00FF"))
+//	// 直前の行から値が変わったセルを強調する
+//	err := e.HighlightWhen("Q6:T20", `Q6<>Q5`, NewStyle().Bold())
+func (e *Excel) HighlightWhen(rangeRef, rule string, style cellStyle) error {
+	rule = strings.TrimPrefix(rule, "=")
+	if rule == "" {
+		return errors.New("HighlightWhen: no rule")
+	}
+	format, err := e.f.NewConditionalStyle(conditionalStyle(style))
+	if err != nil {
+		return fmt.Errorf("HighlightWhen: %w", err)
+	}
+	if err := e.f.SetConditionalFormat(e.sheet, rangeRef,
+		[]excelize.ConditionalFormatOptions{
+			{Type: "formula", Criteria: rule, Format: &format},
+		}); err != nil {
+		return fmt.Errorf("HighlightWhen: %s: %w", rangeRef, err)
+	}
+	return nil
+}
+
+// conditionalStyle converts the style flags to a style of conditional
+// formatting.
+func conditionalStyle(style cellStyle) *excelize.Style {
+	s := &excelize.Style{}
+	font := excelize.Font{Bold: style&fontBold != 0}
+	for _, c := range fontColors {
+		if style&c.style != 0 {
+			font.Color = c.code
+			break
+		}
+	}
+	if font.Bold || font.Color != "" {
+		s.Font = &font
+	}
+	for _, c := range fillColors {
+		if style&c.style != 0 {
+			s.Fill = excelize.Fill{
+				Type: "pattern", Color: []string{c.code}, Pattern: 1}
+			break
+		}
+	}
+	return s
+}
@@ -1,6 +1,7 @@
 package main
 
 import (
+	"errors"
 	"fmt"
 	"log"
 
@@ -11,9 +12,23 @@ func main() {
 	selected, err := tui.FilePicker(
 		"go のソースファイルを選択してください",
 		[]string{".go"})
-	if err != nil {
+	if errors.Is(err, tui.ErrCanceled) {
 		log.Print("ファイルが選択されませんでした")
+	} else if err != nil {
+		log.Fatal(err)
 	} else {
 		fmt.Println("選択されたファイル:", selected)
 	}
+
+	paths, err := tui.FilePickerWithOptions(
+		"保存するファイル名を入力してください",
+		[]string{".xlsx"},
+		tui.FilePickerOptions{Save: true, Quiet: true})
+	if errors.Is(err, tui.ErrCanceled) {
+		log.Print("ファイル名が入力されませんでした")
+	} else if err != nil {
+		log.Fatal(err)
+	} else {
+		fmt.Println("保存するファイル:", paths[0])
+	}
 }
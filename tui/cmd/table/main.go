@@ -1,6 +1,10 @@
 package main
 
 import (
+	"errors"
+	"fmt"
+	"log"
+
 	"github.com/nonsugar-go/tools/tui"
 )
 
@@ -12,4 +16,17 @@ func main() {
 			{"設定ファイル名", "fgt.conf"},
 			{"設定表ファイル名", "fgt.xlsx"},
 		})
+
+	rows := [][]string{
+		{"port1", "192.168.1.99/24", "1500"},
+		{"port2", "10.0.0.1/8", "9000"},
+		{"wan1", "203.0.113.1/24", "1500"},
+	}
+	i, err := tui.ViewTable([]string{"インタフェース", "アドレス", "MTU"}, rows)
+	if errors.Is(err, tui.ErrCanceled) {
+		return
+	} else if err != nil {
+		log.Fatal(err)
+	}
+	fmt.Println("選択:", rows[i][0])
 }
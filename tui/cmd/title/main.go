@@ -3,6 +3,7 @@ package main
 import (
 	"fmt"
 	"log"
+	"time"
 
 	"github.com/nonsugar-go/tools/tui"
 )
@@ -17,5 +18,59 @@ func main() {
 		log.Fatal(err)
 	}
 	fmt.Println(selected)
+	zones, err := tui.MultiSelect("出力するゾーンを選択してください",
+		[]string{"trust", "untrust", "dmz"}, []string{"trust"}, 1)
+	if err != nil {
+		log.Fatal(err)
+	}
+	fmt.Println(zones)
+	port, err := tui.SelectItems("管理ポートを選択してください", []tui.Item[int]{
+		{Label: "HTTPS", Description: "Web UI", Value: 443},
+		{Label: "SSH", Description: "CLI", Value: 22},
+		{Label: "Telnet", Description: "無効化済み", Disabled: true, Value: 23},
+	})
+	if err != nil {
+		log.Fatal(err)
+	}
+	fmt.Println(port)
+	host, err := tui.Input("ホスト名を入力してください", "fw01", nil)
+	if err != nil {
+		log.Fatal(err)
+	}
+	ok, err := tui.Confirm(host+" の設定表を作成しますか?", true)
+	if err != nil {
+		log.Fatal(err)
+	}
+	fmt.Println(host, ok)
+
+	var (
+		device, config, output string
+		overwrite              bool
+	)
+	if err := tui.NewForm("設定表の作成",
+		tui.SelectField("機種", items, &device),
+		tui.FileField("設定ファイル", []string{".conf", ".xml"}, &config),
+		tui.InputField("出力ファイル名", &output, nil),
+		tui.ConfirmField("上書きする", &overwrite)).Run(); err != nil {
+		log.Fatal(err)
+	}
+	fmt.Println(device, config, output, overwrite)
+
+	if err := tui.Progress("設定表を作成しています", func(
+		report func(step, total int, label string)) error {
+		for i := range 20 {
+			report(i+1, 20, fmt.Sprintf("シート %d", i+1))
+			time.Sleep(100 * time.Millisecond)
+		}
+		return nil
+	}); err != nil {
+		log.Fatal(err)
+	}
+	if err := tui.Spinner("保存しています", func() error {
+		time.Sleep(time.Second)
+		return nil
+	}); err != nil {
+		log.Fatal(err)
+	}
 	tui.PressAnyKey("Press Any key to continue...")
 }
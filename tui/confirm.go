@@ -0,0 +1,70 @@
+package tui
+
+import (
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+type confirmModel struct {
+	prompt     string
+	defaultYes bool
+	answer     bool
+	done       bool
+	quitting   bool
+}
+
+func (m confirmModel) Init() tea.Cmd {
+	return nil
+}
+
+func (m confirmModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "ctrl+c", "esc":
+			m.quitting = true
+			return m, tea.Quit
+
+		case "y", "Y":
+			m.answer, m.done = true, true
+			return m, tea.Quit
+
+		case "n", "N":
+			m.answer, m.done = false, true
+			return m, tea.Quit
+
+		case "enter":
+			m.answer, m.done = m.defaultYes, true
+			return m, tea.Quit
+		}
+	}
+	return m, nil
+}
+
+func (m confirmModel) View() string {
+	if m.done || m.quitting {
+		return ""
+	}
+	choices := "(y/N)"
+	if m.defaultYes {
+		choices = "(Y/n)"
+	}
+	return "\n" + titleStyle.Render(m.prompt+" "+choices) + "\n"
+}
+
+// Confirm asks a yes/no question. "y" or "n" answers it, and Enter answers
+// the default. It returns ErrCanceled if canceled by Esc or Ctrl+C, and
+// ErrNoTTY if the standard input is not a terminal.
+//
+// Example:
+//
+//	ok, err := tui.Confirm("既存のファイルを上書きしますか?", false)
+func Confirm(prompt string, defaultYes bool) (bool, error) {
+	m, err := run(confirmModel{prompt: prompt, defaultYes: defaultYes})
+	if err != nil {
+		return false, err
+	}
+	if !m.done {
+		return false, ErrCanceled
+	}
+	return m.answer, nil
+}
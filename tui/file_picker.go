@@ -4,16 +4,50 @@ import (
 	"errors"
 	"fmt"
 	"os"
+	"path/filepath"
+	"slices"
 	"strings"
 	"time"
 
 	"github.com/charmbracelet/bubbles/filepicker"
+	"github.com/charmbracelet/bubbles/textinput"
 	tea "github.com/charmbracelet/bubbletea"
 )
 
+// FilePickerOptions specifies how FilePickerWithOptions chooses the files.
+type FilePickerOptions struct {
+	// Dir is the directory to start in; the empty string is the current
+	// directory.
+	Dir string
+	// ShowHidden shows the hidden files and directories.
+	ShowHidden bool
+	// DirMode chooses a directory instead of a file. Enter opens the
+	// directory, and Ctrl+D chooses the current directory.
+	DirMode bool
+	// Multiple chooses some files. Enter selects or unselects the file, and
+	// Ctrl+D confirms.
+	Multiple bool
+	// Save enters the name of a file to save, which may not exist. Enter on
+	// a file or Tab moves to the name, and the existing file is overwritten
+	// only when confirmed. The first extension is added if the name has
+	// none of the extensions.
+	//
+	// DirMode, Multiple and Save cannot be combined.
+	Save bool
+	// Quiet does not print the chosen paths.
+	Quiet bool
+}
+
 type filePickerModel struct {
 	filepicker   filepicker.Model
+	opts         FilePickerOptions
+	extension    []string
 	selectedFile string
+	selected     []string        // Multiple で選択したファイル
+	name         textinput.Model // Save のファイル名
+	naming       bool            // ファイル名の入力中
+	confirming   bool            // 上書きの確認中
+	done         bool
 	quitting     bool
 	err          error
 	prompt       string
@@ -34,10 +68,49 @@ func (m filePickerModel) Init() tea.Cmd {
 func (m filePickerModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	switch msg := msg.(type) {
 	case tea.KeyMsg:
+		if msg.String() == "ctrl+c" {
+			m.quitting = true
+			return m, tea.Quit
+		}
+		if m.confirming {
+			// 上書きの確認中
+			m.confirming = false
+			if msg.String() == "y" || msg.String() == "Y" {
+				m.done = true
+				return m, tea.Quit
+			}
+			m.selectedFile = ""
+			return m, nil
+		}
+		if m.naming {
+			return m.updateName(msg)
+		}
+
 		switch msg.String() {
-		case "ctrl+c", "q":
+		case "q":
 			m.quitting = true
 			return m, tea.Quit
+
+		case "ctrl+d":
+			switch {
+			case m.opts.DirMode:
+				m.selectedFile = m.filepicker.CurrentDirectory
+				m.done = true
+				return m, tea.Quit
+			case m.opts.Multiple:
+				if len(m.selected) == 0 {
+					m.err = errors.New("ファイルを選択してください")
+					return m, clearErrorAfter(2 * time.Second)
+				}
+				m.done = true
+				return m, tea.Quit
+			}
+
+		case "tab":
+			if m.opts.Save {
+				m.naming = true
+				return m, m.name.Focus()
+			}
 		}
 	case clearErrorMsg:
 		m.err = nil
@@ -48,9 +121,25 @@ func (m filePickerModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 	// Did the user select a file?
 	if didSelect, path := m.filepicker.DidSelectFile(msg); didSelect {
+		switch {
+		case m.opts.Multiple:
+			// 選択済みの場合は解除する
+			if i := slices.Index(m.selected, path); i != -1 {
+				m.selected = slices.Delete(m.selected, i, i+1)
+			} else {
+				m.selected = append(m.selected, path)
+			}
+			return m, cmd
+		case m.opts.Save:
+			// 既存のファイル名を入力欄に設定する
+			m.name.SetValue(filepath.Base(path))
+			m.name.CursorEnd()
+			m.naming = true
+			return m, tea.Batch(cmd, m.name.Focus())
+		}
 		// Get the path of the selected file.
 		m.selectedFile = path
-		m.quitting = true
+		m.done = true
 		return m, tea.Quit
 	}
 
@@ -66,8 +155,47 @@ func (m filePickerModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	return m, cmd
 }
 
+// updateName handles the keys while entering the name of the file to save.
+func (m filePickerModel) updateName(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "tab", "esc":
+		// ファイルの一覧に戻る
+		m.naming = false
+		m.name.Blur()
+		return m, nil
+
+	case "enter":
+		name := strings.TrimSpace(m.name.Value())
+		if name == "" {
+			m.err = errors.New("ファイル名を入力してください")
+			return m, clearErrorAfter(2 * time.Second)
+		}
+		if len(m.extension) > 0 && !slices.ContainsFunc(m.extension,
+			func(ext string) bool { return strings.HasSuffix(name, ext) }) {
+			name += m.extension[0]
+		}
+		m.selectedFile = filepath.Join(m.filepicker.CurrentDirectory, name)
+		info, err := os.Stat(m.selectedFile)
+		switch {
+		case err == nil && info.IsDir():
+			m.err = errors.New(m.selectedFile + " はディレクトリです")
+			m.selectedFile = ""
+			return m, clearErrorAfter(2 * time.Second)
+		case err == nil:
+			m.confirming = true
+			return m, nil
+		}
+		m.done = true
+		return m, tea.Quit
+	}
+
+	var cmd tea.Cmd
+	m.name, cmd = m.name.Update(msg)
+	return m, cmd
+}
+
 func (m filePickerModel) View() string {
-	if m.quitting {
+	if m.done || m.quitting {
 		return ""
 	}
 	var s strings.Builder
@@ -75,32 +203,151 @@ func (m filePickerModel) View() string {
 	s.WriteString(m.filepicker.Styles.Selected.Render(m.prompt) + "\n")
 	if m.err != nil {
 		s.WriteString(m.filepicker.Styles.DisabledFile.Render(m.err.Error()))
-	} else if m.selectedFile == "" {
-		s.WriteString("←: 親ディレクトリ・↑: 上へ・↓: 下へ・enter: 選択")
-		// } else {
-		// s.WriteString("選択: " + m.filepicker.Styles.Selected.Render(m.selectedFile))
+	} else {
+		s.WriteString(m.help())
 	}
 	s.WriteString("\n\n" + m.filepicker.View() + "\n")
+
+	switch {
+	case m.opts.Multiple:
+		for _, path := range m.selected {
+			s.WriteString("\n  " + m.filepicker.Styles.Selected.Render(path))
+		}
+		s.WriteString("\n")
+	case m.opts.Save:
+		s.WriteString("\n  " + m.filepicker.CurrentDirectory +
+			string(filepath.Separator) + m.name.View() + "\n")
+		if m.confirming {
+			s.WriteString(warnStyle.Render(
+				m.selectedFile+" は既に存在します。上書きしますか? (y/N)") + "\n")
+		}
+	}
 	return s.String()
 }
 
-// filepicker is a file selection dialog.
-func FilePicker(prompt string, extension []string) (string, error) {
+// help returns the help of the keys in the mode.
+func (m filePickerModel) help() string {
+	const move = "←: 親ディレクトリ・↑: 上へ・↓: 下へ"
+	switch {
+	case m.naming:
+		return "enter: 保存・tab/esc: ファイルの一覧"
+	case m.opts.DirMode:
+		return move + "・→/enter: 開く・ctrl+d: このディレクトリを選択"
+	case m.opts.Multiple:
+		return move + fmt.Sprintf("・enter: 選択/解除・ctrl+d: 決定 (%d 個)",
+			len(m.selected))
+	case m.opts.Save:
+		return move + "・enter: ファイル名に設定・tab: ファイル名の入力"
+	}
+	return move + "・enter: 選択"
+}
+
+// paths returns the chosen paths.
+func (m filePickerModel) paths() []string {
+	if m.opts.Multiple {
+		return m.selected
+	}
+	return []string{m.selectedFile}
+}
+
+// newFilePickerModel creates the model of FilePicker. The model starts in
+// the current directory if opts.Dir is empty, and in "." if the current
+// directory is not available with the error.
+func newFilePickerModel(prompt string, extension []string,
+	opts FilePickerOptions) (filePickerModel, error) {
+	modes := 0
+	for _, mode := range []bool{opts.DirMode, opts.Multiple, opts.Save} {
+		if mode {
+			modes++
+		}
+	}
+	if modes > 1 {
+		return filePickerModel{}, errors.New(
+			"DirMode, Multiple and Save cannot be combined")
+	}
 	fp := filepicker.New()
 	fp.AllowedTypes = extension
-	// fp.CurrentDirectory, _ = os.UserHomeDir()
-	fp.CurrentDirectory, _ = os.Getwd()
+	fp.ShowHidden = opts.ShowHidden
+	if opts.DirMode {
+		fp.DirAllowed, fp.FileAllowed = true, false
+		// Enter はディレクトリを開くのみとし、選択は ctrl+d で行う
+		fp.KeyMap.Select.SetEnabled(false)
+	}
+
+	name := textinput.New()
+	name.Prompt = ""
+	name.Placeholder = "ファイル名"
 
 	m := filePickerModel{
 		filepicker: fp,
+		opts:       opts,
+		extension:  extension,
+		name:       name,
 		prompt:     prompt,
 	}
-	tm, _ := tea.NewProgram(&m).Run()
-	mm := tm.(filePickerModel)
-	selected := mm.selectedFile
-	if len(selected) > 0 {
-		fmt.Println("\n  選択: " + m.filepicker.Styles.Selected.Render(mm.selectedFile) + "\n")
-		return mm.selectedFile, nil
+
+	dir := opts.Dir
+	if dir == "" {
+		wd, err := os.Getwd()
+		if err != nil {
+			return m, fmt.Errorf("failed to get the current directory: %w", err)
+		}
+		dir = wd
+	}
+	info, err := os.Stat(dir)
+	if err != nil {
+		return m, fmt.Errorf("failed to open the directory: %w", err)
+	}
+	if !info.IsDir() {
+		return m, fmt.Errorf("not a directory: %s", dir)
+	}
+	m.filepicker.CurrentDirectory, err = filepath.Abs(dir)
+	if err != nil {
+		return m, fmt.Errorf("failed to open the directory: %w", err)
+	}
+	return m, nil
+}
+
+// FilePicker is a file selection dialog. It returns ErrCanceled if canceled
+// by "q" or Ctrl+C, and ErrNoTTY if the standard input is not a terminal.
+// See FilePickerWithOptions for the options.
+func FilePicker(prompt string, extension []string) (string, error) {
+	paths, err := FilePickerWithOptions(prompt, extension, FilePickerOptions{})
+	if err != nil {
+		return "", err
+	}
+	return paths[0], nil
+}
+
+// FilePickerWithOptions is FilePicker with the options, which chooses a
+// directory, some files, or a file to save, and returns the chosen paths.
+// It returns an error if two of the modes are set.
+// The paths are printed unless opts.Quiet is set.
+//
+// Example:
+//
+//	paths, err := tui.FilePickerWithOptions("出力ファイルを入力してください",
+//		[]string{".xlsx"}, tui.FilePickerOptions{Dir: "out", Save: true})
+func FilePickerWithOptions(prompt string, extension []string,
+	opts FilePickerOptions) ([]string, error) {
+	m, err := newFilePickerModel(prompt, extension, opts)
+	if err != nil {
+		return nil, err
+	}
+	m, err = run(m)
+	if err != nil {
+		return nil, err
+	}
+	if !m.done {
+		return nil, ErrCanceled
+	}
+	paths := m.paths()
+	if !opts.Quiet {
+		fmt.Println()
+		for _, path := range paths {
+			fmt.Println("  選択: " + m.filepicker.Styles.Selected.Render(path))
+		}
+		fmt.Println()
 	}
-	return "", errors.New("cannot select a file")
+	return paths, nil
 }
@@ -1,8 +1,14 @@
 package tui
 
 import (
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
 	"strings"
 	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
 )
 
 func TestFilePicker(t *testing.T) {
@@ -15,3 +21,147 @@ func TestFilePicker(t *testing.T) {
 		t.Errorf("want *.go, but %s", got)
 	}
 }
+
+// loadFiles sets the size of the screen and reads the directory of the file
+// picker.
+func loadFiles(t *testing.T, m filePickerModel) filePickerModel {
+	t.Helper()
+	tm, _ := m.Update(tea.WindowSizeMsg{Width: 80, Height: 24})
+	tm, _ = tm.Update(m.Init()())
+	return tm.(filePickerModel)
+}
+
+// newTestFilePicker creates the file picker in a directory with the files.
+func newTestFilePicker(t *testing.T, extension []string,
+	opts FilePickerOptions) (filePickerModel, string) {
+	t.Helper()
+	dir := t.TempDir()
+	for _, name := range []string{"a.txt", "b.txt", ".hidden.txt", "sub/c.txt"} {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, nil, 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	opts.Dir = dir
+	m, err := newFilePickerModel("ファイル", extension, opts)
+	if err != nil {
+		t.Fatalf("newFilePickerModel: %v", err)
+	}
+	return loadFiles(t, m), dir
+}
+
+var (
+	keyTab   = tea.KeyMsg{Type: tea.KeyTab}
+	keyCtrlD = tea.KeyMsg{Type: tea.KeyCtrlD}
+	keyUp    = tea.KeyMsg{Type: tea.KeyUp}
+)
+
+func TestFilePickerOptions_Dir(t *testing.T) {
+	if _, err := newFilePickerModel("ファイル", nil,
+		FilePickerOptions{Dir: filepath.Join(t.TempDir(), "none")}); err == nil {
+		t.Error("missing directory: want error, but nil")
+	}
+	for _, opts := range []FilePickerOptions{
+		{DirMode: true, Multiple: true},
+		{DirMode: true, Save: true},
+		{Multiple: true, Save: true},
+	} {
+		if _, err := FilePickerWithOptions("ファイル", nil, opts); err == nil ||
+			errors.Is(err, ErrNoTTY) {
+			t.Errorf("%+v: want error, but %v", opts, err)
+		}
+	}
+	m, dir := newTestFilePicker(t, nil, FilePickerOptions{})
+	if m.filepicker.CurrentDirectory != dir {
+		t.Errorf("want %s, but %s", dir, m.filepicker.CurrentDirectory)
+	}
+	// 並び順: sub, a.txt, b.txt
+	m = press(m, keyDown, keyEnter).(filePickerModel)
+	if want := filepath.Join(dir, "a.txt"); !m.done || m.selectedFile != want {
+		t.Errorf("want %s, but %s (done: %v)", want, m.selectedFile, m.done)
+	}
+}
+
+func TestFilePickerOptions_ShowHidden(t *testing.T) {
+	m, _ := newTestFilePicker(t, nil, FilePickerOptions{ShowHidden: true})
+	if !strings.Contains(m.View(), ".hidden.txt") {
+		t.Errorf("want .hidden.txt, but %q", m.View())
+	}
+	m, _ = newTestFilePicker(t, nil, FilePickerOptions{})
+	if strings.Contains(m.View(), ".hidden.txt") {
+		t.Errorf("want no .hidden.txt, but %q", m.View())
+	}
+}
+
+func TestFilePickerOptions_DirMode(t *testing.T) {
+	m, dir := newTestFilePicker(t, nil, FilePickerOptions{DirMode: true})
+	// enter はディレクトリを開くのみ
+	tm, cmd := m.Update(keyEnter)
+	m = tm.(filePickerModel)
+	if m.done {
+		t.Fatal("enter: want not done, but done")
+	}
+	tm, _ = m.Update(cmd())
+	m = press(tm, keyCtrlD).(filePickerModel)
+	if want := filepath.Join(dir, "sub"); !m.done || m.selectedFile != want {
+		t.Errorf("want %s, but %s (done: %v)", want, m.selectedFile, m.done)
+	}
+}
+
+func TestFilePickerOptions_Multiple(t *testing.T) {
+	m, dir := newTestFilePicker(t, nil, FilePickerOptions{Multiple: true})
+	m = press(m, keyCtrlD).(filePickerModel)
+	if m.done || m.err == nil {
+		t.Errorf("no file: want error, but done: %v", m.done)
+	}
+	// a.txt と b.txt を選択して a.txt を解除し、再度選択する
+	m = press(m, keyDown, keyEnter, keyDown, keyEnter, keyUp, keyEnter, keyEnter,
+		keyCtrlD).(filePickerModel)
+	want := []string{filepath.Join(dir, "b.txt"), filepath.Join(dir, "a.txt")}
+	if !m.done || !slices.Equal(m.paths(), want) {
+		t.Errorf("want %v, but %v (done: %v)", want, m.paths(), m.done)
+	}
+}
+
+func TestFilePickerOptions_Save(t *testing.T) {
+	txt := []string{".txt"}
+	tests := []struct {
+		name      string
+		extension []string
+		keys      []tea.KeyMsg
+		want      string
+		done      bool
+	}{
+		{"new file", txt, []tea.KeyMsg{keyTab, keyMsg("new"), keyEnter},
+			"new.txt", true},
+		{"with extension", txt,
+			[]tea.KeyMsg{keyTab, keyMsg("new.txt"), keyEnter}, "new.txt", true},
+		{"no extension", nil, []tea.KeyMsg{keyTab, keyMsg("new"), keyEnter},
+			"new", true},
+		{"overwrite", txt,
+			[]tea.KeyMsg{keyDown, keyEnter, keyEnter, keyMsg("y")}, "a.txt", true},
+		{"not overwrite", txt,
+			[]tea.KeyMsg{keyDown, keyEnter, keyEnter, keyMsg("n")}, "", false},
+		{"back to files", txt, []tea.KeyMsg{keyTab, keyEsc, keyDown, keyDown,
+			keyEnter, keyEnter, keyMsg("y")}, "b.txt", true},
+		{"empty", txt, []tea.KeyMsg{keyTab, keyEnter}, "", false},
+		{"directory", nil, []tea.KeyMsg{keyTab, keyMsg("sub"), keyEnter},
+			"", false},
+	}
+	for _, tt := range tests {
+		m, dir := newTestFilePicker(t, tt.extension,
+			FilePickerOptions{Save: true})
+		m = press(m, tt.keys...).(filePickerModel)
+		want := ""
+		if tt.want != "" {
+			want = filepath.Join(dir, tt.want)
+		}
+		if m.done != tt.done || m.selectedFile != want {
+			t.Errorf("%s: want %s (done: %v), but %s (done: %v)",
+				tt.name, want, tt.done, m.selectedFile, m.done)
+		}
+	}
+}
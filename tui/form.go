@@ -0,0 +1,290 @@
+package tui
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/list"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+var summaryLabelStyle = lipgloss.NewStyle().PaddingLeft(4).Bold(true)
+
+// fieldModel is the model of a field of Form.
+type fieldModel interface {
+	tea.Model
+	// finished reports whether the field is answered or canceled.
+	finished() (done, canceled bool)
+	// reopen returns the model which can be answered again.
+	reopen() fieldModel
+	// summary returns the answer shown in the summary.
+	summary() string
+}
+
+// Field is a question of Form, created by SelectField, MultiSelectField,
+// FileField, InputField or ConfirmField.
+type Field struct {
+	title string
+	model fieldModel
+	apply func(fieldModel) // 回答を変数に設定する
+}
+
+// SelectField is a field choosing one of the choices, as Select. The value
+// is chosen first if it is one of the choices.
+func SelectField(title string, choices []string, value *string) Field {
+	items := []list.Item{}
+	for _, choice := range choices {
+		items = append(items, selectItem(choice))
+	}
+	m := newSelectModel(title, items, itemDelegate{})
+	if i := slices.Index(choices, *value); i != -1 {
+		m.list.Select(i)
+	}
+	return Field{title: title, model: m, apply: func(m fieldModel) {
+		*value = m.(selectModel).choice
+	}}
+}
+
+// MultiSelectField is a field checking the choices, as MultiSelect. The
+// value is checked first.
+func MultiSelectField(title string, choices []string, value *[]string,
+	limits ...int) Field {
+	return Field{title: title,
+		model: newMultiSelectModel(title, choices, *value, limits...),
+		apply: func(m fieldModel) {
+			*value = m.(multiSelectModel).selected()
+		}}
+}
+
+// FileField is a field choosing a file with the extensions, as FilePicker.
+// It starts in "." if the current directory is not available.
+func FileField(title string, extension []string, value *string) Field {
+	// エラーの場合も "." から選択できる
+	m, _ := newFilePickerModel(title, extension, FilePickerOptions{})
+	return Field{title: title, model: m,
+		apply: func(m fieldModel) {
+			*value = m.(filePickerModel).selectedFile
+		}}
+}
+
+// InputField is a field reading a line of text, as Input. The value is the
+// initial value.
+func InputField(title string, value *string,
+	validate func(string) error) Field {
+	return Field{title: title, model: newInputModel(title, *value, validate),
+		apply: func(m fieldModel) {
+			*value = m.(inputModel).input.Value()
+		}}
+}
+
+// ConfirmField is a field asking a yes/no question, as Confirm. The value
+// is the default answer.
+func ConfirmField(title string, value *bool) Field {
+	return Field{title: title,
+		model: confirmModel{prompt: title, defaultYes: *value},
+		apply: func(m fieldModel) {
+			*value = m.(confirmModel).answer
+		}}
+}
+
+func (m selectModel) finished() (bool, bool) { return m.done, m.quitting }
+
+func (m selectModel) reopen() fieldModel {
+	m.done, m.quitting, m.warn = false, false, ""
+	return m
+}
+
+func (m selectModel) summary() string { return m.choice }
+
+func (m multiSelectModel) finished() (bool, bool) { return m.done, m.quitting }
+
+func (m multiSelectModel) reopen() fieldModel {
+	m.done, m.quitting, m.warn = false, false, ""
+	return m
+}
+
+func (m multiSelectModel) summary() string {
+	return strings.Join(m.selected(), ", ")
+}
+
+func (m filePickerModel) finished() (bool, bool) { return m.done, m.quitting }
+
+func (m filePickerModel) reopen() fieldModel {
+	m.selectedFile, m.done, m.quitting = "", false, false
+	return m
+}
+
+func (m filePickerModel) summary() string { return m.selectedFile }
+
+func (m inputModel) finished() (bool, bool) { return m.done, m.quitting }
+
+func (m inputModel) reopen() fieldModel {
+	m.done, m.quitting = false, false
+	return m
+}
+
+func (m inputModel) summary() string { return m.input.Value() }
+
+func (m confirmModel) finished() (bool, bool) { return m.done, m.quitting }
+
+func (m confirmModel) reopen() fieldModel {
+	m.done, m.quitting = false, false
+	return m
+}
+
+func (m confirmModel) summary() string {
+	if m.answer {
+		return "はい"
+	}
+	return "いいえ"
+}
+
+// Form is a wizard asking the fields one by one in a screen.
+type Form struct {
+	title  string
+	fields []Field
+}
+
+// NewForm creates a form of the fields.
+//
+// Example:
+//
+//	var (
+//		device, config, output string
+//		overwrite              bool
+//	)
+//	form := tui.NewForm("設定表の作成",
+//		tui.SelectField("機種", []string{"PaloAlto", "FortiGate"}, &device),
+//		tui.FileField("設定ファイル", []string{".conf", ".xml"}, &config),
+//		tui.InputField("出力ファイル名", &output, nil),
+//		tui.ConfirmField("上書きする", &overwrite))
+//	err := form.Run()
+func NewForm(title string, fields ...Field) *Form {
+	return &Form{title: title, fields: fields}
+}
+
+type formModel struct {
+	title    string
+	fields   []Field
+	current  int // 回答中のフィールド (len(fields) はまとめのページ)
+	done     bool
+	quitting bool
+}
+
+func (m formModel) Init() tea.Cmd {
+	var cmds []tea.Cmd
+	for _, f := range m.fields {
+		cmds = append(cmds, f.model.Init())
+	}
+	return tea.Batch(cmds...)
+}
+
+func (m formModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	if msg, ok := msg.(tea.KeyMsg); ok {
+		switch msg.String() {
+		case "ctrl+c":
+			m.quitting = true
+			return m, tea.Quit
+
+		case "shift+tab":
+			return m.back(), nil
+		}
+		if m.current == len(m.fields) {
+			// まとめのページ
+			switch msg.String() {
+			case "enter":
+				m.done = true
+				return m, tea.Quit
+			case "esc", "q":
+				return m.back(), nil
+			}
+			return m, nil
+		}
+
+		// キー入力は回答中のフィールドのみに送る
+		f := &m.fields[m.current]
+		tm, cmd := f.model.Update(msg)
+		f.model = tm.(fieldModel)
+		done, canceled := f.model.finished()
+		switch {
+		case done:
+			// フィールドの tea.Quit は捨てる
+			m.current++
+			return m, nil
+		case canceled:
+			f.model = f.model.reopen()
+			return m.back(), nil
+		}
+		return m, cmd
+	}
+
+	// キー入力以外 (画面の大きさ、カーソルの点滅など) はすべてのフィールドに送る
+	var cmds []tea.Cmd
+	for i := range m.fields {
+		tm, cmd := m.fields[i].model.Update(msg)
+		m.fields[i].model = tm.(fieldModel)
+		cmds = append(cmds, cmd)
+	}
+	return m, tea.Batch(cmds...)
+}
+
+// back returns to the previous field.
+func (m formModel) back() formModel {
+	if m.current > 0 {
+		m.current--
+	}
+	f := &m.fields[m.current]
+	f.model = f.model.reopen()
+	return m
+}
+
+func (m formModel) View() string {
+	if m.done || m.quitting {
+		return ""
+	}
+	step := fmt.Sprintf(" (%d/%d)", m.current+1, len(m.fields)+1)
+	s := "\n" + titleStyle.Render(m.title+step) + "\n"
+	if m.current < len(m.fields) {
+		return s + m.fields[m.current].model.View() + "\n" +
+			helpStyle.Render("shift+tab: 戻る・ctrl+c: 中止")
+	}
+
+	// まとめのページ
+	s += "\n"
+	for _, f := range m.fields {
+		s += summaryLabelStyle.Render(f.title+":") + " " + f.model.summary() + "\n"
+	}
+	return s + "\n" + helpStyle.Render("enter: 決定・esc: 戻る・ctrl+c: 中止")
+}
+
+// Run asks the fields one by one, and shows the summary of the answers.
+// Enter answers a field and goes to the next one, and Shift+Tab goes back
+// to the previous one; so do Esc and "q" in the fields which cancel by
+// them. The answers are set to the values of the fields only when the
+// summary is confirmed by Enter.
+//
+// It returns ErrCanceled if canceled by Ctrl+C, and ErrNoTTY if the
+// standard input is not a terminal.
+func (f *Form) Run() error {
+	if len(f.fields) == 0 {
+		return nil
+	}
+	m, err := run(formModel{title: f.title, fields: slices.Clone(f.fields)})
+	if err != nil {
+		return err
+	}
+	if !m.done {
+		return ErrCanceled
+	}
+	m.apply()
+	return nil
+}
+
+// apply sets the answers to the values of the fields.
+func (m formModel) apply() {
+	for _, f := range m.fields {
+		f.apply(f.model)
+	}
+}
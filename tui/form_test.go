@@ -0,0 +1,79 @@
+package tui
+
+import (
+	"slices"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestForm(t *testing.T) {
+	var (
+		device    = "PaloAlto"
+		vdoms     []string
+		output    = "out"
+		overwrite bool
+	)
+	form := NewForm("設定表の作成",
+		SelectField("機種", []string{"PaloAlto", "FortiGate"}, &device),
+		MultiSelectField("VDOM", []string{"root", "dmz"}, &vdoms, 1),
+		InputField("出力ファイル名", &output, nil),
+		ConfirmField("上書きする", &overwrite))
+	keyBack := tea.KeyMsg{Type: tea.KeyShiftTab}
+
+	m := formModel{title: form.title, fields: slices.Clone(form.fields)}
+	m = press(m, keyDown, keyEnter).(formModel)
+	if m.current != 1 {
+		t.Fatalf("Form: want field 1, but %d", m.current)
+	}
+	// 最小数に満たない場合は次へ進まない
+	m = press(m, keyEnter).(formModel)
+	if m.current != 1 {
+		t.Errorf("Form: want field 1 for validation, but %d", m.current)
+	}
+	m = press(m, keySpace, keyEnter, keyMsg(".xlsx"), keyEnter).(formModel)
+	if m.current != 3 {
+		t.Fatalf("Form: want field 3, but %d", m.current)
+	}
+	// 戻っても入力は残る
+	m = press(m, keyBack).(formModel)
+	if m.current != 2 || !strings.Contains(m.View(), "out.xlsx") {
+		t.Errorf("Form: want field 2 with out.xlsx, but %d: %s",
+			m.current, m.View())
+	}
+	m = press(m, keyEnter, keyEsc).(formModel)
+	if m.current != 2 {
+		t.Errorf("Form: want field 2 by Esc, but %d", m.current)
+	}
+	m = press(m, keyEnter, keyMsg("y")).(formModel)
+	if m.current != 4 {
+		t.Fatalf("Form: want summary, but %d", m.current)
+	}
+	view := m.View()
+	for _, want := range []string{"機種:", "FortiGate", "root", "out.xlsx",
+		"はい"} {
+		if !strings.Contains(view, want) {
+			t.Errorf("View: want %q, but %s", want, view)
+		}
+	}
+	if device != "PaloAlto" {
+		t.Errorf("Form: want no change before confirmation, but %s", device)
+	}
+	m = press(m, keyEnter).(formModel)
+	if !m.done {
+		t.Fatalf("Form: want done")
+	}
+	m.apply()
+	if device != "FortiGate" || !slices.Equal(vdoms, []string{"root"}) ||
+		output != "out.xlsx" || !overwrite {
+		t.Errorf("Form: want FortiGate [root] out.xlsx true, but %s %v %s %v",
+			device, vdoms, output, overwrite)
+	}
+
+	m = press(formModel{title: form.title, fields: slices.Clone(form.fields)},
+		tea.KeyMsg{Type: tea.KeyCtrlC}).(formModel)
+	if !m.quitting {
+		t.Errorf("Form: want canceled by Ctrl+C")
+	}
+}
@@ -0,0 +1,119 @@
+package tui
+
+import (
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+type inputModel struct {
+	input    textinput.Model
+	prompt   string
+	validate func(string) error
+	err      error // 入力値の検証エラー
+	done     bool
+	quitting bool
+}
+
+func (m inputModel) Init() tea.Cmd {
+	return textinput.Blink
+}
+
+func (m inputModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.input.Width = msg.Width - len(m.input.Prompt) - 4
+		return m, nil
+
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "ctrl+c", "esc":
+			m.quitting = true
+			return m, tea.Quit
+
+		case "enter":
+			if m.validate != nil {
+				if m.err = m.validate(m.input.Value()); m.err != nil {
+					return m, nil
+				}
+			}
+			m.done = true
+			return m, tea.Quit
+		}
+	}
+
+	var cmd tea.Cmd
+	m.input, cmd = m.input.Update(msg)
+	if _, ok := msg.(tea.KeyMsg); ok {
+		m.err = nil
+	}
+	return m, cmd
+}
+
+func (m inputModel) View() string {
+	if m.done || m.quitting {
+		return ""
+	}
+	s := "\n" + titleStyle.Render(m.prompt) + "\n\n" + itemStyle.Render(m.input.View())
+	if m.err != nil {
+		s += "\n" + warnStyle.Render(m.err.Error())
+	}
+	return s + "\n" + helpStyle.Render("enter: 決定・esc: 中止")
+}
+
+// newInputModel creates the model of Input and Password.
+func newInputModel(prompt, value string, validate func(string) error) inputModel {
+	ti := textinput.New()
+	ti.SetValue(value)
+	ti.Focus()
+	return inputModel{
+		input:    ti,
+		prompt:   prompt,
+		validate: validate,
+	}
+}
+
+// Input reads a line of text. The default is shown as the initial value,
+// which the user can edit. If validate is not nil, Enter is accepted only
+// when it returns nil, and its error is shown under the input. It returns
+// ErrCanceled if canceled by Esc or Ctrl+C, and ErrNoTTY if the standard
+// input is not a terminal.
+//
+// Example:
+//
+//	host, err := tui.Input("ホスト名を入力してください", "fw01",
+//		func(s string) error {
+//			if s == "" {
+//				return errors.New("ホスト名は必須です")
+//			}
+//			return nil
+//		})
+func Input(prompt, def string, validate func(string) error) (string, error) {
+	m, err := run(newInputModel(prompt, def, validate))
+	if err != nil {
+		return "", err
+	}
+	if !m.done {
+		return "", ErrCanceled
+	}
+	return m.input.Value(), nil
+}
+
+// Password reads a secret such as an API key, without echoing it.
+// It returns ErrCanceled if canceled, as Input.
+//
+// Example:
+//
+//	key, err := tui.Password("API キーを入力してください")
+func Password(prompt string) (string, error) {
+	m := newInputModel(prompt, "", nil)
+	m.input.EchoMode = textinput.EchoPassword
+	m.input.EchoCharacter = '•'
+	m, err := run(m)
+	if err != nil {
+		return "", err
+	}
+	if !m.done {
+		return "", ErrCanceled
+	}
+	return m.input.Value(), nil
+}
@@ -0,0 +1,93 @@
+package tui
+
+import (
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// keyMsg returns the key message of typing the text.
+func keyMsg(text string) tea.KeyMsg {
+	return tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(text)}
+}
+
+var keyEsc = tea.KeyMsg{Type: tea.KeyEsc}
+
+func TestInput(t *testing.T) {
+	validate := func(s string) error {
+		if !strings.HasPrefix(s, "fw") {
+			return errors.New("fw で始めてください")
+		}
+		return nil
+	}
+	m := press(newInputModel("ホスト名", "fw", validate), keyMsg("01"),
+		keyEnter).(inputModel)
+	if !m.done || m.input.Value() != "fw01" {
+		t.Errorf("Input: want fw01, but %s (done %v)", m.input.Value(), m.done)
+	}
+
+	m = press(newInputModel("ホスト名", "", validate), keyMsg("sw01"),
+		keyEnter).(inputModel)
+	if m.done {
+		t.Errorf("Input: want not done for invalid value")
+	}
+	if !strings.Contains(m.View(), "fw で始めてください") {
+		t.Errorf("View: want validation error, but %s", m.View())
+	}
+
+	m = press(newInputModel("ホスト名", "fw01", nil), keyEsc).(inputModel)
+	if m.done || !m.quitting {
+		t.Errorf("Input: want canceled")
+	}
+}
+
+func TestConfirm(t *testing.T) {
+	tests := []struct {
+		defaultYes bool
+		key        tea.KeyMsg
+		want, done bool
+	}{
+		{true, keyEnter, true, true},
+		{false, keyEnter, false, true},
+		{false, keyMsg("y"), true, true},
+		{true, keyMsg("n"), false, true},
+		{true, keyMsg("x"), false, false},
+		{true, keyEsc, false, false},
+	}
+	for _, tt := range tests {
+		m := press(confirmModel{prompt: "上書きしますか?",
+			defaultYes: tt.defaultYes}, tt.key).(confirmModel)
+		if m.answer != tt.want || m.done != tt.done {
+			t.Errorf("Confirm(%v, %q): want %v (done %v), but %v (done %v)",
+				tt.defaultYes, tt.key, tt.want, tt.done, m.answer, m.done)
+		}
+	}
+	if view := (confirmModel{prompt: "上書きしますか?"}).View(); !strings.Contains(
+		view, "(y/N)") {
+		t.Errorf("View: want (y/N), but %s", view)
+	}
+}
+
+func TestTextArea(t *testing.T) {
+	m := press(newTextAreaModel("変更内容", "1行目"), keyEnter,
+		keyMsg("2行目"), tea.KeyMsg{Type: tea.KeyCtrlD}).(textAreaModel)
+	if !m.done || m.textarea.Value() != "1行目\n2行目" {
+		t.Errorf("TextArea: want 2 lines, but %q (done %v)",
+			m.textarea.Value(), m.done)
+	}
+
+	// 後ろの文字は delete で削除する
+	m = press(newTextAreaModel("変更内容", "abc"), tea.KeyMsg{Type: tea.KeyLeft},
+		tea.KeyMsg{Type: tea.KeyDelete}).(textAreaModel)
+	if m.done || m.textarea.Value() != "ab" {
+		t.Errorf("TextArea: want ab, but %q (done %v)", m.textarea.Value(),
+			m.done)
+	}
+	if keys := m.textarea.KeyMap.DeleteCharacterForward.Keys(); slices.Contains(
+		keys, "ctrl+d") {
+		t.Errorf("DeleteCharacterForward: want no ctrl+d, but %v", keys)
+	}
+}
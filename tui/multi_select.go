@@ -0,0 +1,209 @@
+package tui
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/list"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+var (
+	counterStyle = lipgloss.NewStyle().PaddingLeft(4)
+	warnStyle    = lipgloss.NewStyle().PaddingLeft(4).Foreground(lipgloss.Color("203"))
+)
+
+var (
+	toggleKey    = key.NewBinding(key.WithKeys(" "), key.WithHelp("space", "選択/解除"))
+	toggleAllKey = key.NewBinding(key.WithKeys("a"), key.WithHelp("a", "すべて選択/解除"))
+	confirmKey   = key.NewBinding(key.WithKeys("enter"), key.WithHelp("enter", "決定"))
+)
+
+// multiSelectItem is an item with a checkbox.
+type multiSelectItem struct {
+	name    string
+	checked bool
+}
+
+func (i multiSelectItem) FilterValue() string { return i.name }
+
+type multiSelectModel struct {
+	list     list.Model
+	min, max int // 選択数の下限と上限 (0 は制限なし)
+	warn     string
+	done     bool
+	quitting bool
+}
+
+func (m multiSelectModel) Init() tea.Cmd {
+	return nil
+}
+
+func (m multiSelectModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.list.SetWidth(msg.Width)
+		return m, nil
+
+	case tea.KeyMsg:
+		m.warn = ""
+		switch {
+		case msg.String() == "ctrl+c" || key.Matches(msg, m.list.KeyMap.Quit):
+			m.quitting = true
+			return m, tea.Quit
+
+		case key.Matches(msg, toggleKey):
+			i, ok := m.list.SelectedItem().(multiSelectItem)
+			if !ok {
+				return m, nil
+			}
+			if !i.checked && m.max > 0 && m.count() >= m.max {
+				m.warn = fmt.Sprintf("%d 個まで選択できます", m.max)
+				return m, nil
+			}
+			i.checked = !i.checked
+			return m, m.list.SetItem(m.list.Index(), i)
+
+		case key.Matches(msg, toggleAllKey):
+			// すべて選択済みの場合は、すべて解除する
+			checked := m.count() < len(m.list.Items())
+			if checked && m.max > 0 && len(m.list.Items()) > m.max {
+				m.warn = fmt.Sprintf("%d 個まで選択できます", m.max)
+				return m, nil
+			}
+			var cmds []tea.Cmd
+			for index, item := range m.list.Items() {
+				i := item.(multiSelectItem)
+				i.checked = checked
+				cmds = append(cmds, m.list.SetItem(index, i))
+			}
+			return m, tea.Batch(cmds...)
+
+		case key.Matches(msg, confirmKey):
+			if m.count() < m.min {
+				m.warn = fmt.Sprintf("%d 個以上選択してください", m.min)
+				return m, nil
+			}
+			// 上限を超えて preselected を指定した場合
+			if m.max > 0 && m.count() > m.max {
+				m.warn = fmt.Sprintf("%d 個まで選択できます", m.max)
+				return m, nil
+			}
+			m.done = true
+			return m, tea.Quit
+		}
+	}
+
+	var cmd tea.Cmd
+	m.list, cmd = m.list.Update(msg)
+	return m, cmd
+}
+
+func (m multiSelectModel) View() string {
+	if m.done {
+		return quitTextStyle.Render(fmt.Sprintf("%d 個を選択", m.count()))
+	}
+	if m.quitting {
+		return quitTextStyle.Render("選択しませんでした")
+	}
+	counter := fmt.Sprintf("%d/%d 個を選択", m.count(), len(m.list.Items()))
+	switch {
+	case m.min > 0 && m.max > 0:
+		counter += fmt.Sprintf(" (%d〜%d 個)", m.min, m.max)
+	case m.min > 0:
+		counter += fmt.Sprintf(" (%d 個以上)", m.min)
+	case m.max > 0:
+		counter += fmt.Sprintf(" (%d 個まで)", m.max)
+	}
+	s := "\n" + m.list.View() + "\n" + counterStyle.Render(counter)
+	if m.warn != "" {
+		s += "\n" + warnStyle.Render(m.warn)
+	}
+	return s
+}
+
+// count returns the number of the checked items.
+func (m multiSelectModel) count() int {
+	n := 0
+	for _, item := range m.list.Items() {
+		if i, ok := item.(multiSelectItem); ok && i.checked {
+			n++
+		}
+	}
+	return n
+}
+
+// selected returns the names of the checked items in the order of the
+// choices.
+func (m multiSelectModel) selected() []string {
+	var names []string
+	for _, item := range m.list.Items() {
+		if i, ok := item.(multiSelectItem); ok && i.checked {
+			names = append(names, i.name)
+		}
+	}
+	return names
+}
+
+// newMultiSelectModel creates the model of MultiSelect.
+func newMultiSelectModel(prompt string, choices, preselected []string,
+	limits ...int) multiSelectModel {
+	items := []list.Item{}
+	for _, choice := range choices {
+		items = append(items, multiSelectItem{
+			name:    choice,
+			checked: slices.Contains(preselected, choice),
+		})
+	}
+
+	const defaultWidth = 20
+
+	l := list.New(items, itemDelegate{}, defaultWidth, listHeight)
+	l.Title = prompt
+	l.SetShowStatusBar(false)
+	l.SetFilteringEnabled(false)
+	l.Styles.Title = titleStyle
+	l.Styles.PaginationStyle = paginationStyle
+	l.Styles.HelpStyle = helpStyle
+	l.AdditionalShortHelpKeys = func() []key.Binding {
+		return []key.Binding{toggleKey, toggleAllKey, confirmKey}
+	}
+
+	m := multiSelectModel{list: l}
+	if len(limits) > 0 {
+		m.min = limits[0]
+		if len(limits) > 1 {
+			m.max = limits[1]
+		}
+	}
+	return m
+}
+
+// MultiSelect shows the choices with checkboxes, and returns the checked
+// choices in the order of the choices. The preselected choices are checked
+// first. Space toggles the item, "a" checks or unchecks all the items, and
+// Enter confirms. It returns ErrCanceled if canceled, as Select.
+//
+// The optional limits are the minimum and the maximum number of the checked
+// items; 0 means no limit. Enter is not accepted until the number is in
+// the limits, even if more choices than the maximum are preselected.
+//
+// Example:
+//
+//	vdoms, err := tui.MultiSelect("出力する VDOM を選択してください",
+//		[]string{"root", "dmz", "guest"}, []string{"root"}, 1)
+func MultiSelect(prompt string, choices, preselected []string,
+	limits ...int) ([]string, error) {
+	m := newMultiSelectModel(prompt, choices, preselected, limits...)
+
+	m, err := run(m)
+	if err != nil {
+		return nil, err
+	}
+	if !m.done {
+		return nil, ErrCanceled
+	}
+	return m.selected(), nil
+}
@@ -0,0 +1,81 @@
+package tui
+
+import (
+	"slices"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// press sends the keys to the model.
+func press(m tea.Model, keys ...tea.KeyMsg) tea.Model {
+	for _, k := range keys {
+		m, _ = m.Update(k)
+	}
+	return m
+}
+
+var (
+	keySpace = tea.KeyMsg{Type: tea.KeySpace, Runes: []rune{' '}}
+	keyDown  = tea.KeyMsg{Type: tea.KeyDown}
+	keyEnter = tea.KeyMsg{Type: tea.KeyEnter}
+	keyA     = tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'a'}}
+)
+
+func TestMultiSelect(t *testing.T) {
+	choices := []string{"root", "dmz", "guest", "mgmt"}
+	tests := []struct {
+		name        string
+		preselected []string
+		limits      []int
+		keys        []tea.KeyMsg
+		want        []string
+		done        bool
+	}{
+		{"preselected", []string{"guest"}, nil,
+			[]tea.KeyMsg{keyEnter}, []string{"guest"}, true},
+		{"toggle", []string{"root"}, nil,
+			[]tea.KeyMsg{keySpace, keyDown, keySpace, keyDown, keyDown, keySpace,
+				keyEnter},
+			[]string{"dmz", "mgmt"}, true},
+		{"select all", nil, nil,
+			[]tea.KeyMsg{keyA, keyEnter}, choices, true},
+		{"unselect all", choices, nil,
+			[]tea.KeyMsg{keyA, keyEnter}, nil, true},
+		{"min", nil, []int{1},
+			[]tea.KeyMsg{keyEnter}, nil, false},
+		{"max", nil, []int{0, 2},
+			[]tea.KeyMsg{keySpace, keyDown, keySpace, keyDown, keySpace, keyA,
+				keyEnter},
+			[]string{"root", "dmz"}, true},
+		{"preselected over max", choices, []int{0, 2},
+			[]tea.KeyMsg{keyEnter}, choices, false},
+		{"uncheck to max", choices, []int{0, 2},
+			[]tea.KeyMsg{keyEnter, keySpace, keyDown, keySpace, keyEnter},
+			[]string{"guest", "mgmt"}, true},
+	}
+	for _, tt := range tests {
+		m := press(newMultiSelectModel("VDOM", choices, tt.preselected,
+			tt.limits...), tt.keys...).(multiSelectModel)
+		if m.done != tt.done {
+			t.Errorf("%s: want done %v, but %v", tt.name, tt.done, m.done)
+		}
+		if got := m.selected(); !slices.Equal(got, tt.want) {
+			t.Errorf("%s: want %v, but %v", tt.name, tt.want, got)
+		}
+	}
+
+	m := newMultiSelectModel("VDOM", choices, []string{"dmz"}, 1, 3)
+	view := m.View()
+	for _, want := range []string{"[x] 2. dmz", "[ ] 1. root", "1/4 個を選択",
+		"(1〜3 個)"} {
+		if !strings.Contains(view, want) {
+			t.Errorf("View: want %q, but %s", want, view)
+		}
+	}
+	m = press(m, keyDown, keySpace, keyEnter).(multiSelectModel)
+	if !strings.Contains(m.View(), "1 個以上選択してください") {
+		t.Errorf("View: want warning, but %s", m.View())
+	}
+}
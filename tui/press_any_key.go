@@ -35,8 +35,7 @@ func PressAnyKey(msg ...string) error {
 	if len(msg) != 0 {
 		message = msg[0]
 	}
-	p := tea.NewProgram(pressAnyKeyModel{msg: message})
-	if _, err := p.Run(); err != nil {
+	if _, err := run(pressAnyKeyModel{msg: message}); err != nil {
 		return fmt.Errorf("cannot start PressAnyKey: %w", err)
 	}
 	return nil
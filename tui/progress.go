@@ -0,0 +1,287 @@
+package tui
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/spinner"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/term"
+)
+
+const (
+	// progressBarWidth is the maximum width of the bar of Progress.
+	progressBarWidth = 40
+	// canceledTaskText is shown while waiting for the canceled task.
+	canceledTaskText = "中止しました。処理の終了を待っています"
+)
+
+var (
+	progressFilledStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("170"))
+	progressEmptyStyle  = lipgloss.NewStyle().Foreground(disabledColor)
+	progressLabelStyle  = lipgloss.NewStyle().Foreground(disabledColor)
+)
+
+// taskProgressMsg is the progress reported by the task.
+type taskProgressMsg struct {
+	step, total int
+	label       string
+}
+
+// taskDoneMsg is sent when the task returns.
+type taskDoneMsg struct {
+	err error
+}
+
+// taskState is the running task shared by runTask and the model.
+type taskState struct {
+	events chan tea.Msg  // 経過 (taskProgressMsg)
+	done   chan struct{} // タスクが終わると閉じる
+	err    error         // タスクのエラー (done を閉じる前に設定する)
+}
+
+// newTaskState returns the state of a task which reports the progress to
+// events with the buffer of size; size 0 means no progress.
+func newTaskState(size int) *taskState {
+	t := &taskState{done: make(chan struct{})}
+	if size > 0 {
+		t.events = make(chan tea.Msg, size)
+	}
+	return t
+}
+
+// waitForTask waits for the next message from the task. Once the task
+// returns, it returns taskDoneMsg, so that a command left after the model
+// quits does not block forever or take the result from runTask.
+func waitForTask(t *taskState) tea.Cmd {
+	return func() tea.Msg {
+		select {
+		case msg := <-t.events:
+			return msg
+		case <-t.done:
+			return taskDoneMsg{err: t.err}
+		}
+	}
+}
+
+// taskModel is the model showing a running task.
+type taskModel interface {
+	tea.Model
+	// result returns whether the task returned, and its error.
+	result() (done bool, err error)
+}
+
+// runTask runs the task in a goroutine while showing the model. When the
+// standard input is not a terminal, the task runs without the model. If
+// the model quits before the task returns, runTask waits for the task,
+// which cannot be stopped.
+func runTask[M taskModel](m M, t *taskState, task func() error) error {
+	if !term.IsTerminal(os.Stdin.Fd()) {
+		// 端末でない場合は表示せずに実行する
+		return task()
+	}
+	return runTaskProgram(m, t, task)
+}
+
+// runTaskProgram is runTask on a terminal, with the options of the program.
+func runTaskProgram[M taskModel](m M, t *taskState, task func() error,
+	opts ...tea.ProgramOption) error {
+	go func() {
+		t.err = task()
+		close(t.done)
+	}()
+	m, err := runProgram(m, opts...)
+	if err == nil {
+		done, taskErr := m.result()
+		if done {
+			return taskErr
+		}
+		err = ErrCanceled
+	}
+	// 書き込み中のファイルなどを壊さないよう、タスクの終了を待つ
+	<-t.done
+	return err
+}
+
+type progressModel struct {
+	title       string
+	task        *taskState
+	start       time.Time
+	step, total int
+	label       string
+	width       int // バーの幅
+	done        bool
+	err         error
+	quitting    bool
+}
+
+func (m progressModel) Init() tea.Cmd {
+	return waitForTask(m.task)
+}
+
+func (m progressModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.width = min(progressBarWidth, msg.Width-4)
+		return m, nil
+
+	case tea.KeyMsg:
+		if msg.String() == "ctrl+c" {
+			m.quitting = true
+			return m, tea.Quit
+		}
+
+	case taskProgressMsg:
+		m.step, m.total, m.label = msg.step, msg.total, msg.label
+		return m, waitForTask(m.task)
+
+	case taskDoneMsg:
+		m.done, m.err = true, msg.err
+		return m, tea.Quit
+	}
+	return m, nil
+}
+
+func (m progressModel) View() string {
+	if m.quitting {
+		return quitTextStyle.Render(canceledTaskText)
+	}
+	if m.done {
+		return ""
+	}
+	var s strings.Builder
+	s.WriteString("\n" + titleStyle.Render(m.title) + "\n\n  ")
+	if m.total > 0 {
+		filled := m.width * min(m.step, m.total) / m.total
+		s.WriteString(progressFilledStyle.Render(strings.Repeat("█", filled)))
+		s.WriteString(progressEmptyStyle.Render(
+			strings.Repeat("░", m.width-filled)))
+		fmt.Fprintf(&s, " %d/%d", m.step, m.total)
+		if eta, ok := estimate(time.Since(m.start), m.step, m.total); ok {
+			fmt.Fprintf(&s, " 残り %s", eta)
+		}
+	}
+	s.WriteString("\n  " + progressLabelStyle.Render(m.label) + "\n")
+	return s.String()
+}
+
+// estimate returns the remaining time estimated from the elapsed time of
+// the steps.
+func estimate(elapsed time.Duration, step, total int) (time.Duration, bool) {
+	if step <= 0 || total <= 0 || step > total {
+		return 0, false
+	}
+	remaining := elapsed / time.Duration(step) * time.Duration(total-step)
+	return remaining.Round(time.Second), true
+}
+
+func (m progressModel) result() (bool, error) { return m.done, m.err }
+
+// Progress runs the task while showing a progress bar with the step count
+// and the estimated remaining time, and returns the error of the task. The
+// task calls report to update the progress; report can be passed to
+// excel.SetProgress as it is. The steps count from 1 to total, and total 0
+// shows only the label.
+//
+// Ctrl+C hides the progress and returns ErrCanceled, but only after the
+// task returns, since the task cannot be stopped; for example, the
+// workbook written by SaveAndClose is not left broken. When the standard
+// input is not a terminal, the task runs without the progress.
+//
+// Example:
+//
+//	err := tui.Progress("設定表を作成しています", func(
+//		report func(step, total int, label string)) error {
+//		e.SetProgress(report)
+//		...
+//		return e.SaveAndClose()
+//	})
+func Progress(title string,
+	task func(report func(step, total int, label string)) error) error {
+	t := newTaskState(64)
+	report := func(step, total int, label string) {
+		// 表示が追いつかない場合は捨てる
+		select {
+		case t.events <- taskProgressMsg{step, total, label}:
+		default:
+		}
+	}
+	return runTask(progressModel{
+		title: title,
+		task:  t,
+		start: time.Now(),
+		width: progressBarWidth,
+	}, t, func() error { return task(report) })
+}
+
+type spinnerModel struct {
+	title    string
+	task     *taskState
+	start    time.Time
+	spinner  spinner.Model
+	done     bool
+	err      error
+	quitting bool
+}
+
+func (m spinnerModel) Init() tea.Cmd {
+	return tea.Batch(m.spinner.Tick, waitForTask(m.task))
+}
+
+func (m spinnerModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		if msg.String() == "ctrl+c" {
+			m.quitting = true
+			return m, tea.Quit
+		}
+
+	case taskDoneMsg:
+		m.done, m.err = true, msg.err
+		return m, tea.Quit
+
+	case spinner.TickMsg:
+		var cmd tea.Cmd
+		m.spinner, cmd = m.spinner.Update(msg)
+		return m, cmd
+	}
+	return m, nil
+}
+
+func (m spinnerModel) View() string {
+	if m.quitting {
+		return quitTextStyle.Render(canceledTaskText)
+	}
+	if m.done {
+		return ""
+	}
+	elapsed := time.Since(m.start).Round(time.Second)
+	return fmt.Sprintf("\n  %s %s %s\n", m.spinner.View(), m.title,
+		progressLabelStyle.Render(elapsed.String()))
+}
+
+func (m spinnerModel) result() (bool, error) { return m.done, m.err }
+
+// Spinner runs the task while showing a spinner with the elapsed time, and
+// returns the error of the task. It is for the tasks whose steps are
+// unknown; see Progress otherwise. Ctrl+C and no terminal are the same as
+// Progress.
+//
+// Example:
+//
+//	err := tui.Spinner("保存しています", e.SaveAndClose)
+func Spinner(title string, task func() error) error {
+	s := spinner.New()
+	s.Spinner = spinner.Dot
+	s.Style = selectedItemStyle.UnsetPaddingLeft()
+	t := newTaskState(0)
+	return runTask(spinnerModel{
+		title:   title,
+		task:    t,
+		start:   time.Now(),
+		spinner: s,
+	}, t, task)
+}
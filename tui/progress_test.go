@@ -0,0 +1,120 @@
+package tui
+
+import (
+	"errors"
+	"io"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/charmbracelet/bubbles/spinner"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/x/term"
+)
+
+func TestEstimate(t *testing.T) {
+	tests := []struct {
+		elapsed     time.Duration
+		step, total int
+		want        time.Duration
+		ok          bool
+	}{
+		{10 * time.Second, 1, 4, 30 * time.Second, true},
+		{10 * time.Second, 4, 4, 0, true},
+		{10 * time.Second, 0, 4, 0, false},
+		{10 * time.Second, 1, 0, 0, false},
+	}
+	for _, tt := range tests {
+		got, ok := estimate(tt.elapsed, tt.step, tt.total)
+		if got != tt.want || ok != tt.ok {
+			t.Errorf("estimate(%v, %d, %d): want %v %v, but %v %v",
+				tt.elapsed, tt.step, tt.total, tt.want, tt.ok, got, ok)
+		}
+	}
+}
+
+func TestProgress(t *testing.T) {
+	m := progressModel{title: "作成中", start: time.Now(),
+		width: progressBarWidth}
+	tm, _ := m.Update(taskProgressMsg{5, 10, "WriteDF"})
+	view := tm.View()
+	for _, want := range []string{"作成中", "5/10", "残り", "WriteDF",
+		strings.Repeat("█", progressBarWidth/2)} {
+		if !strings.Contains(view, want) {
+			t.Errorf("View: want %q, but %s", want, view)
+		}
+	}
+	errTask := errors.New("task failed")
+	tm, _ = tm.Update(taskDoneMsg{err: errTask})
+	if done, err := tm.(progressModel).result(); !done || err != errTask {
+		t.Errorf("result: want done with error, but %v %v", done, err)
+	}
+
+	tm, _ = m.Update(tea.KeyMsg{Type: tea.KeyCtrlC})
+	if !strings.Contains(tm.View(), canceledTaskText) {
+		t.Errorf("View: want %q, but %s", canceledTaskText, tm.View())
+	}
+
+	if term.IsTerminal(os.Stdin.Fd()) {
+		t.Skip("standard input is a terminal")
+	}
+	// 端末でない場合はそのまま実行する
+	steps := 0
+	err := Progress("作成中", func(report func(int, int, string)) error {
+		for i := range 3 {
+			report(i+1, 3, "step")
+			steps++
+		}
+		return errTask
+	})
+	if steps != 3 || err != errTask {
+		t.Errorf("Progress: want 3 steps and the task error, but %d %v",
+			steps, err)
+	}
+	if err := Spinner("保存中", func() error { return nil }); err != nil {
+		t.Errorf("Spinner: want no error, but %v", err)
+	}
+}
+
+func TestRunTaskCanceled(t *testing.T) {
+	// 実行中のタスクを Ctrl+C で中止しても、タスクの終了後に戻る
+	for _, name := range []string{"Progress", "Spinner"} {
+		state := newTaskState(0)
+		var m taskModel = spinnerModel{title: name, task: state,
+			spinner: spinner.New()}
+		if name == "Progress" {
+			state = newTaskState(2)
+			m = progressModel{title: name, task: state,
+				width: progressBarWidth}
+		}
+		finished := false
+		task := func() error {
+			time.Sleep(300 * time.Millisecond)
+			// バッファを超えて報告しても止まらない
+			for i := range 10 {
+				select {
+				case state.events <- taskProgressMsg{i + 1, 10, "step"}:
+				default:
+				}
+			}
+			finished = true
+			return nil
+		}
+		result := make(chan error, 1)
+		go func() {
+			result <- runTaskProgram(m, state, task,
+				tea.WithInput(strings.NewReader("\x03")),
+				tea.WithOutput(io.Discard), tea.WithoutSignalHandler())
+		}()
+		select {
+		case err := <-result:
+			if !errors.Is(err, ErrCanceled) || !finished {
+				t.Errorf("%s: want ErrCanceled after the task, but %v (%v)",
+					name, err, finished)
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatalf("%s: want to return after Ctrl+C, but blocked", name)
+		}
+	}
+}
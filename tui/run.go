@@ -0,0 +1,46 @@
+package tui
+
+import (
+	"errors"
+	"fmt"
+	"os"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/x/term"
+)
+
+var (
+	// ErrCanceled is returned when the user cancels by "q", Ctrl+C or Esc.
+	ErrCanceled = errors.New("tui: canceled by user")
+	// ErrNoTTY is returned when the standard input is not a terminal, such
+	// as in a pipe or a scheduled job.
+	ErrNoTTY = errors.New("tui: not a terminal")
+)
+
+// run runs the model as a bubbletea program, and returns the final model.
+// The terminal state is restored by the program before returning, even if
+// it fails.
+func run[M tea.Model](m M) (M, error) {
+	if !term.IsTerminal(os.Stdin.Fd()) {
+		return m, ErrNoTTY
+	}
+	return runProgram(m)
+}
+
+// runProgram is run without checking the terminal, with the options of the
+// program.
+func runProgram[M tea.Model](m M, opts ...tea.ProgramOption) (M, error) {
+	tm, err := tea.NewProgram(m, opts...).Run()
+	if errors.Is(err, tea.ErrInterrupted) {
+		return m, ErrCanceled
+	}
+	if err != nil {
+		return m, fmt.Errorf("Error running program: %w", err)
+	}
+	mm, ok := tm.(M)
+	if !ok {
+		return m, fmt.Errorf(
+			"type assertion failed: model is %T, not %T", tm, m)
+	}
+	return mm, nil
+}
@@ -0,0 +1,24 @@
+package tui
+
+import (
+	"errors"
+	"os"
+	"testing"
+
+	"github.com/charmbracelet/x/term"
+)
+
+func TestRunNoTTY(t *testing.T) {
+	if term.IsTerminal(os.Stdin.Fd()) {
+		t.Skip("standard input is a terminal")
+	}
+	if _, err := Select("機種", []string{"PaloAlto"}); !errors.Is(err, ErrNoTTY) {
+		t.Errorf("Select: want ErrNoTTY, but %v", err)
+	}
+	if _, err := MultiSelect("機種", []string{"PaloAlto"}, nil); !errors.Is(err, ErrNoTTY) {
+		t.Errorf("MultiSelect: want ErrNoTTY, but %v", err)
+	}
+	if _, err := FilePicker("ファイル", nil); !errors.Is(err, ErrNoTTY) {
+		t.Errorf("FilePicker: want ErrNoTTY, but %v", err)
+	}
+}
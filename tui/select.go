@@ -1,11 +1,11 @@
 package tui
 
 import (
-	"errors"
 	"fmt"
 	"io"
 	"strings"
 
+	"github.com/charmbracelet/bubbles/key"
 	"github.com/charmbracelet/bubbles/list"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
@@ -14,6 +14,10 @@ import (
 // const listHeight = 14
 const listHeight = 8
 
+// selectChrome is the number of the lines of the list other than the items,
+// such as the title, the pagination and the help.
+const selectChrome = 6
+
 var (
 	titleStyle        = lipgloss.NewStyle().MarginLeft(2)
 	itemStyle         = lipgloss.NewStyle().PaddingLeft(4)
@@ -21,24 +25,57 @@ var (
 	paginationStyle   = list.DefaultStyles().PaginationStyle.PaddingLeft(4)
 	helpStyle         = list.DefaultStyles().HelpStyle.PaddingLeft(4).PaddingBottom(1)
 	quitTextStyle     = lipgloss.NewStyle().Margin(1, 0, 2, 4)
+	matchedStyle      = lipgloss.NewStyle().Underline(true).Bold(true)
+	disabledColor     = lipgloss.Color("241")
+	descriptionStyle  = lipgloss.NewStyle().PaddingLeft(7).Foreground(disabledColor)
 )
 
 type selectItem string
 
-func (i selectItem) FilterValue() string { return "" }
+func (i selectItem) FilterValue() string { return string(i) }
+
+type itemDelegate struct {
+	description bool // 説明を 2 行目に表示する
+}
 
-type itemDelegate struct{}
+func (d itemDelegate) Height() int {
+	if d.description {
+		return 2
+	}
+	return 1
+}
 
-func (d itemDelegate) Height() int                             { return 1 }
 func (d itemDelegate) Spacing() int                            { return 0 }
 func (d itemDelegate) Update(_ tea.Msg, _ *list.Model) tea.Cmd { return nil }
 func (d itemDelegate) Render(w io.Writer, m list.Model, index int, listItem list.Item) {
-	i, ok := listItem.(selectItem)
-	if !ok {
-		return
+	// フィルタに一致した文字を強調する
+	highlight := func(label string, disabled bool) string {
+		unmatched := lipgloss.NewStyle()
+		if disabled {
+			unmatched = unmatched.Foreground(disabledColor)
+		} else if index == m.Index() {
+			unmatched = unmatched.Foreground(selectedItemStyle.GetForeground())
+		}
+		return lipgloss.StyleRunes(label, m.MatchesForItem(index),
+			matchedStyle.Inherit(unmatched), unmatched)
 	}
 
-	str := fmt.Sprintf("%d. %s", index+1, i)
+	var str, description string
+	switch i := listItem.(type) {
+	case selectItem:
+		str = fmt.Sprintf("%d. %s", index+1, highlight(string(i), false))
+	case richItem:
+		str = fmt.Sprintf("%d. %s", index+1, highlight(i.label, i.disabled))
+		description = i.description
+	case multiSelectItem:
+		checkbox := "[ ]"
+		if i.checked {
+			checkbox = "[x]"
+		}
+		str = fmt.Sprintf("%s %d. %s", checkbox, index+1, i.name)
+	default:
+		return
+	}
 
 	fn := itemStyle.Render
 	if index == m.Index() {
@@ -48,11 +85,19 @@ func (d itemDelegate) Render(w io.Writer, m list.Model, index int, listItem list
 	}
 
 	fmt.Fprint(w, fn(str))
+	if d.description {
+		fmt.Fprint(w, "\n"+descriptionStyle.Render(description))
+	}
 }
 
 type selectModel struct {
 	list     list.Model
+	delegate itemDelegate
+	height   int // リストの高さの上限 (0 は端末の高さ)
 	choice   string
+	index    int // 選択した richItem の添字
+	warn     string
+	done     bool
 	quitting bool
 }
 
@@ -64,19 +109,42 @@ func (m selectModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	switch msg := msg.(type) {
 	case tea.WindowSizeMsg:
 		m.list.SetWidth(msg.Width)
+		m.list.SetHeight(selectHeight(
+			len(m.list.Items())*m.delegate.Height(), m.height, msg.Height))
 		return m, nil
 
 	case tea.KeyMsg:
+		if m.list.SettingFilter() {
+			// フィルタの入力中はリストに任せる
+			break
+		}
+		m.warn = ""
+		// リストの終了キー (q と Esc) は中止とする。ただし Esc によるフィルタの解除を除く
+		if key.Matches(msg, m.list.KeyMap.Quit) &&
+			!key.Matches(msg, m.list.KeyMap.ClearFilter) {
+			m.quitting = true
+			return m, tea.Quit
+		}
 		switch keypress := msg.String(); keypress {
-		case "q", "ctrl+c":
+		case "ctrl+c":
 			m.quitting = true
 			return m, tea.Quit
 
 		case "enter":
-			i, ok := m.list.SelectedItem().(selectItem)
-			if ok {
+			switch i := m.list.SelectedItem().(type) {
+			case selectItem:
 				m.choice = string(i)
+			case richItem:
+				if i.disabled {
+					m.warn = fmt.Sprintf("%s は選択できません", i.label)
+					return m, nil
+				}
+				m.choice, m.index = i.label, i.index
+			default:
+				// 項目がない場合やフィルタに一致しない場合は終了しない
+				return m, nil
 			}
+			m.done = true
 			return m, tea.Quit
 		}
 	}
@@ -87,43 +155,81 @@ func (m selectModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 }
 
 func (m selectModel) View() string {
-	if m.choice != "" {
+	if m.done {
 		return quitTextStyle.Render(fmt.Sprintf("%s を選択", m.choice))
 	}
 	if m.quitting {
 		return quitTextStyle.Render("選択しませんでした")
 	}
-	return "\n" + m.list.View()
+	s := "\n" + m.list.View()
+	if m.warn != "" {
+		s += "\n" + warnStyle.Render(m.warn)
+	}
+	return s
 }
 
-func Select(prompt string, choices []string) (string, error) {
-	items := []list.Item{}
-	for _, item := range choices {
-		items = append(
-			items, selectItem(item))
+// selectHeight returns the height of the list of n lines, which is at most
+// maxHeight and fits the terminal. The terminal height 0 means unknown.
+func selectHeight(n, maxHeight, termHeight int) int {
+	height := n + selectChrome
+	if maxHeight > 0 && height > maxHeight {
+		height = maxHeight
+	}
+	if termHeight <= 0 {
+		termHeight = listHeight + selectChrome
 	}
+	if height > termHeight-1 {
+		height = termHeight - 1
+	}
+	return height
+}
 
+// newSelectModel creates the model of Select and SelectItems.
+func newSelectModel(prompt string, items []list.Item, delegate itemDelegate,
+	height ...int) selectModel {
 	const defaultWidth = 20
 
-	l := list.New(items, itemDelegate{}, defaultWidth, listHeight)
+	m := selectModel{delegate: delegate}
+	if len(height) > 0 {
+		m.height = height[0]
+	}
+	l := list.New(items, delegate, defaultWidth,
+		selectHeight(len(items)*delegate.Height(), m.height, 0))
 	l.Title = prompt
 	l.SetShowStatusBar(false)
-	l.SetFilteringEnabled(false)
 	l.Styles.Title = titleStyle
 	l.Styles.PaginationStyle = paginationStyle
 	l.Styles.HelpStyle = helpStyle
+	m.list = l
+	return m
+}
 
-	m := selectModel{list: l}
+// Select shows the choices, and returns the chosen one. "/" starts typing
+// a filter, which matches the choices fuzzily and highlights the matched
+// characters. Enter is ignored while no choice is shown, such as when the
+// filter matches nothing. It returns ErrCanceled if canceled by "q", Esc
+// or Ctrl+C, and ErrNoTTY if the standard input is not a terminal.
+//
+// The optional height is the maximum number of the lines of the list;
+// by default, the list fits the choices and the terminal.
+//
+// Example:
+//
+//	iface, err := tui.Select("インタフェースを選択してください", ifaces, 20)
+func Select(prompt string, choices []string, height ...int) (string, error) {
+	items := []list.Item{}
+	for _, item := range choices {
+		items = append(
+			items, selectItem(item))
+	}
+	m := newSelectModel(prompt, items, itemDelegate{}, height...)
 
-	var teaModel tea.Model
-	teaModel, err := tea.NewProgram(m).Run()
+	m, err := run(m)
 	if err != nil {
-		return "", fmt.Errorf("Error running program: %w", err)
+		return "", err
 	}
-	m, ok := teaModel.(selectModel)
-	if !ok {
-		return "", errors.New(
-			"type assertion failed: model is not of type selectModel")
+	if !m.done {
+		return "", ErrCanceled
 	}
 	return m.choice, nil
 }
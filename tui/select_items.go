@@ -0,0 +1,70 @@
+package tui
+
+import (
+	"errors"
+
+	"github.com/charmbracelet/bubbles/list"
+)
+
+// Item is a choice of SelectItems.
+type Item[T any] struct {
+	Label       string // 表示する名前
+	Description string // 名前の下に表示する説明
+	Disabled    bool   // 選択できない
+	Value       T      // 選択した場合に返す値
+}
+
+// richItem is an item of SelectItems.
+type richItem struct {
+	label       string
+	description string
+	disabled    bool
+	index       int // Item の添字
+}
+
+func (i richItem) FilterValue() string { return i.label }
+
+// SelectItems shows the labels of the items with their descriptions, and
+// returns the value of the chosen item. The disabled items are shown but
+// cannot be chosen. It returns ErrCanceled if canceled, as Select, and an
+// error if items is empty.
+//
+// Filtering and the optional height are the same as Select.
+//
+// Example:
+//
+//	model, err := tui.SelectItems("機種を選択してください", []tui.Item[Model]{
+//		{Label: "PaloAlto", Description: "PAN-OS 11.x", Value: ModelPaloAlto},
+//		{Label: "FortiGate", Description: "FortiOS 7.x", Value: ModelFortiGate},
+//	})
+func SelectItems[T any](prompt string, items []Item[T], height ...int) (
+	T, error) {
+	var zero T
+	if len(items) == 0 {
+		return zero, errors.New("SelectItems: no items")
+	}
+	listItems := []list.Item{}
+	description := false
+	for i, item := range items {
+		listItems = append(listItems, richItem{
+			label:       item.Label,
+			description: item.Description,
+			disabled:    item.Disabled,
+			index:       i,
+		})
+		if item.Description != "" {
+			description = true
+		}
+	}
+	m := newSelectModel(prompt, listItems,
+		itemDelegate{description: description}, height...)
+
+	m, err := run(m)
+	if err != nil {
+		return zero, err
+	}
+	if !m.done {
+		return zero, ErrCanceled
+	}
+	return items[m.index].Value, nil
+}
@@ -0,0 +1,53 @@
+package tui
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/charmbracelet/bubbles/list"
+)
+
+func TestSelectItems(t *testing.T) {
+	items := []list.Item{
+		richItem{label: "PaloAlto", description: "PAN-OS 11.x", index: 0},
+		richItem{label: "FortiGate", description: "FortiOS 7.x", index: 1,
+			disabled: true},
+		richItem{label: "SRX", description: "Junos", index: 2},
+	}
+	delegate := itemDelegate{description: true}
+	if got := delegate.Height(); got != 2 {
+		t.Errorf("Height: want 2, but %d", got)
+	}
+
+	m := newSelectModel("機種", items, delegate)
+	view := m.View()
+	for _, want := range []string{"1. PaloAlto", "PAN-OS 11.x", "Junos"} {
+		if !strings.Contains(view, want) {
+			t.Errorf("View: want %q, but %s", want, view)
+		}
+	}
+
+	// 選択できない項目
+	m = press(m, keyDown, keyEnter).(selectModel)
+	if m.done {
+		t.Errorf("Select: want not done for disabled item")
+	}
+	if !strings.Contains(m.View(), "FortiGate は選択できません") {
+		t.Errorf("View: want warning, but %s", m.View())
+	}
+	m = press(m, keyDown, keyEnter).(selectModel)
+	if !m.done || m.index != 2 || m.choice != "SRX" {
+		t.Errorf("Select: want SRX (2), but %s (%d)", m.choice, m.index)
+	}
+
+	// 項目がない場合
+	m = press(newSelectModel("機種", nil, delegate), keyEnter).(selectModel)
+	if m.done {
+		t.Errorf("Select: want not done for no items")
+	}
+	if _, err := SelectItems("機種", []Item[int]{}); err == nil ||
+		errors.Is(err, ErrCanceled) {
+		t.Errorf("SelectItems: want error for no items, but %v", err)
+	}
+}
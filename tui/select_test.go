@@ -0,0 +1,81 @@
+package tui
+
+import (
+	"testing"
+
+	"github.com/charmbracelet/bubbles/list"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// selectItems converts the choices to the items of Select.
+func selectItems(choices []string) []list.Item {
+	var items []list.Item
+	for _, choice := range choices {
+		items = append(items, selectItem(choice))
+	}
+	return items
+}
+
+func TestSelect(t *testing.T) {
+	choices := []string{"port1", "port2", "wan1", "wan2"}
+
+	m := press(newSelectModel("インタフェース", selectItems(choices), itemDelegate{}), keyDown,
+		keyEnter).(selectModel)
+	if m.choice != "port2" {
+		t.Errorf("Select: want port2, but %s", m.choice)
+	}
+
+	// あいまい検索で絞り込む
+	m = newSelectModel("インタフェース", selectItems(choices), itemDelegate{})
+	m.list.SetFilterText("wn2")
+	if got := len(m.list.VisibleItems()); got != 1 {
+		t.Errorf("SetFilterText: want 1 item, but %d", got)
+	}
+	if got := m.list.MatchesForItem(0); len(got) != 3 {
+		t.Errorf("MatchesForItem: want 3 runes, but %v", got)
+	}
+	m = press(m, keyEnter).(selectModel)
+	if m.choice != "wan2" {
+		t.Errorf("Select: want wan2, but %s", m.choice)
+	}
+
+	// フィルタに一致しない場合は空の選択で終了しない
+	m = newSelectModel("インタフェース", selectItems(choices), itemDelegate{})
+	m.list.SetFilterText("xyz")
+	m = press(m, keyEnter).(selectModel)
+	if m.done || m.choice != "" {
+		t.Errorf("Select: want not done, but %v %q", m.done, m.choice)
+	}
+
+	// フィルタの入力中の q は終了しない
+	keyQ := tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'q'}}
+	keySlash := tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'/'}}
+	m = press(newSelectModel("インタフェース", selectItems(choices), itemDelegate{}), keySlash,
+		keyQ).(selectModel)
+	if m.quitting {
+		t.Errorf("Select: want not quitting while filtering")
+	}
+	m = press(newSelectModel("インタフェース", selectItems(choices), itemDelegate{}), keyQ).(selectModel)
+	if !m.quitting {
+		t.Errorf("Select: want quitting")
+	}
+}
+
+func TestSelectHeight(t *testing.T) {
+	tests := []struct {
+		n, maxHeight, termHeight int
+		want                     int
+	}{
+		{2, 0, 40, 2 + selectChrome},
+		{200, 0, 40, 39},
+		{200, 20, 40, 20},
+		{200, 60, 40, 39},
+		{200, 0, 0, listHeight + selectChrome - 1},
+	}
+	for _, tt := range tests {
+		if got := selectHeight(tt.n, tt.maxHeight, tt.termHeight); got != tt.want {
+			t.Errorf("selectHeight(%d, %d, %d): want %d, but %d",
+				tt.n, tt.maxHeight, tt.termHeight, tt.want, got)
+		}
+	}
+}
@@ -2,39 +2,233 @@ package tui
 
 import (
 	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
 
 	"github.com/charmbracelet/lipgloss"
 	"github.com/charmbracelet/lipgloss/table"
+	"github.com/charmbracelet/x/term"
 )
 
+// defaultTerminalWidth is the width used when the width of the terminal
+// is unknown.
+const defaultTerminalWidth = 80
+
+// Alignment is the horizontal alignment of a column of PrintTable.
+type Alignment int
+
+const (
+	AlignLeft Alignment = iota
+	AlignCenter
+	AlignRight
+)
+
+// TableFormat is the output format of PrintTable.
+type TableFormat int
+
+const (
+	// TableAuto is TableStyled on a terminal, and TableMarkdown otherwise.
+	TableAuto TableFormat = iota
+	TableStyled
+	TableASCII
+	TableMarkdown
+	TableTSV
+)
+
+// TableOptions specifies how PrintTable prints a table.
+type TableOptions struct {
+	// Align is the alignment of each column; missing columns are left.
+	Align []Alignment
+	// Format is the output format.
+	Format TableFormat
+}
+
 // PrintTable() is a function that outputs in tabular form
-func PrintTable(headers []string, rows [][]string) {
+//
+// The widths of the columns fit the display widths of the cells, including
+// full-width characters, and the table is wrapped to the width of the
+// terminal. When the standard output is not a terminal, such as a pipe or
+// a log, the table is printed as Markdown without colors.
+//
+// Example:
+//
+//	tui.PrintTable([]string{"項目", "件数"}, rows,
+//		tui.TableOptions{Align: []tui.Alignment{tui.AlignLeft, tui.AlignRight}})
+func PrintTable(headers []string, rows [][]string, opts ...TableOptions) {
+	var opt TableOptions
+	if len(opts) > 0 {
+		opt = opts[0]
+	}
+	isTTY := term.IsTerminal(os.Stdout.Fd())
+	if opt.Format == TableAuto {
+		opt.Format = TableMarkdown
+		if isTTY {
+			opt.Format = TableStyled
+		}
+	}
+	writeTable(os.Stdout, headers, rows, opt, terminalWidth())
+}
+
+// terminalWidth returns the width of the standard output, the COLUMNS
+// environment variable, or defaultTerminalWidth.
+func terminalWidth() int {
+	if width, _, err := term.GetSize(os.Stdout.Fd()); err == nil && width > 0 {
+		return width
+	}
+	if width, err := strconv.Atoi(os.Getenv("COLUMNS")); err == nil &&
+		width > 0 {
+		return width
+	}
+	return defaultTerminalWidth
+}
+
+// writeTable writes the table in the format, which is not TableAuto.
+func writeTable(w io.Writer, headers []string, rows [][]string,
+	opt TableOptions, width int) {
+	// 列数は見出しと行の最大
+	n := len(headers)
+	for _, row := range rows {
+		n = max(n, len(row))
+	}
+	if n == 0 {
+		return
+	}
+	align := func(col int) Alignment {
+		if col < len(opt.Align) {
+			return opt.Align[col]
+		}
+		return AlignLeft
+	}
+
+	switch opt.Format {
+	case TableTSV:
+		writeTSV(w, headers, rows)
+	case TableMarkdown:
+		writeMarkdown(w, headers, rows, n, align)
+	default:
+		fmt.Fprintln(w, styledTable(headers, rows, opt.Format, align, width))
+	}
+}
+
+// styledTable returns the table drawn by lipgloss, which fits the width.
+func styledTable(headers []string, rows [][]string, format TableFormat,
+	align func(int) Alignment, width int) *table.Table {
 	var (
 		purple    = lipgloss.Color("99")
 		gray      = lipgloss.Color("245")
 		lightGray = lipgloss.Color("241")
 
-		headerStyle  = lipgloss.NewStyle().Foreground(purple).Bold(true).Align(lipgloss.Center)
-		cellStyle    = lipgloss.NewStyle().Padding(0, 1).Width(64 / len(headers))
-		oddRowStyle  = cellStyle.Foreground(gray)
-		evenRowStyle = cellStyle.Foreground(lightGray)
+		headerStyle  = lipgloss.NewStyle().Bold(true).Align(lipgloss.Center)
+		cellStyle    = lipgloss.NewStyle().Padding(0, 1)
+		oddRowStyle  = cellStyle
+		evenRowStyle = cellStyle
+		borderStyle  = lipgloss.NewStyle()
+		border       = lipgloss.ASCIIBorder()
 	)
+	if format == TableStyled {
+		headerStyle = headerStyle.Foreground(purple)
+		oddRowStyle = cellStyle.Foreground(gray)
+		evenRowStyle = cellStyle.Foreground(lightGray)
+		borderStyle = borderStyle.Foreground(purple)
+		border = lipgloss.NormalBorder()
+	}
+	positions := []lipgloss.Position{lipgloss.Left, lipgloss.Center,
+		lipgloss.Right}
 
 	t := table.New().
-		Border(lipgloss.NormalBorder()).
-		BorderStyle(lipgloss.NewStyle().Foreground(purple)).
+		Border(border).
+		BorderStyle(borderStyle).
 		StyleFunc(func(row, col int) lipgloss.Style {
 			switch {
 			case row == table.HeaderRow:
 				return headerStyle
 			case row%2 == 0:
-				return evenRowStyle
+				return evenRowStyle.Align(positions[align(col)])
 			default:
-				return oddRowStyle
+				return oddRowStyle.Align(positions[align(col)])
 			}
 		}).
 		Headers(headers...).
 		Rows(rows...)
+	// 端末の幅を超える場合のみ、幅を指定して折り返す
+	if lipgloss.Width(t.String()) > width {
+		t.Width(width)
+	}
+	return t
+}
+
+// writeMarkdown writes the table as Markdown, padding the cells by the
+// display width.
+func writeMarkdown(w io.Writer, headers []string, rows [][]string, n int,
+	align func(int) Alignment) {
+	escape := func(s string) string {
+		s = strings.ReplaceAll(s, "|", `\|`)
+		return strings.ReplaceAll(s, "\n", "<br>")
+	}
+	cell := func(row []string, col int) string {
+		if col < len(row) {
+			return escape(row[col])
+		}
+		return ""
+	}
+	widths := make([]int, n)
+	for col := range n {
+		widths[col] = max(3, lipgloss.Width(cell(headers, col)))
+		for _, row := range rows {
+			widths[col] = max(widths[col], lipgloss.Width(cell(row, col)))
+		}
+	}
+	line := func(row []string) {
+		var sb strings.Builder
+		sb.WriteString("|")
+		for col := range n {
+			s := cell(row, col)
+			pad := strings.Repeat(" ", widths[col]-lipgloss.Width(s))
+			if align(col) == AlignRight {
+				sb.WriteString(" " + pad + s + " |")
+			} else {
+				sb.WriteString(" " + s + pad + " |")
+			}
+		}
+		fmt.Fprintln(w, sb.String())
+	}
+
+	line(headers)
+	var sb strings.Builder
+	sb.WriteString("|")
+	for col := range n {
+		dashes := strings.Repeat("-", widths[col])
+		switch align(col) {
+		case AlignCenter:
+			dashes = ":" + dashes[1:len(dashes)-1] + ":"
+		case AlignRight:
+			dashes = dashes[1:] + ":"
+		}
+		sb.WriteString(" " + dashes + " |")
+	}
+	fmt.Fprintln(w, sb.String())
+	for _, row := range rows {
+		line(row)
+	}
+}
 
-	fmt.Println(t)
+// writeTSV writes the table as tab-separated values. Tabs and new lines in
+// the cells are replaced with spaces.
+func writeTSV(w io.Writer, headers []string, rows [][]string) {
+	replacer := strings.NewReplacer("\t", " ", "\r\n", " ", "\n", " ")
+	line := func(row []string) {
+		cells := make([]string, len(row))
+		for i, cell := range row {
+			cells[i] = replacer.Replace(cell)
+		}
+		fmt.Fprintln(w, strings.Join(cells, "\t"))
+	}
+	if len(headers) > 0 {
+		line(headers)
+	}
+	for _, row := range rows {
+		line(row)
+	}
 }
@@ -0,0 +1,88 @@
+package tui
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+func TestWriteTable(t *testing.T) {
+	headers := []string{"項目", "件数"}
+	rows := [][]string{
+		{"アドレス", "12"},
+		{"a|b", "3"},
+	}
+	align := []Alignment{AlignLeft, AlignRight}
+	tests := []struct {
+		format TableFormat
+		want   string
+	}{
+		{TableMarkdown, "" +
+			"| 項目     | 件数 |\n" +
+			"| -------- | ---: |\n" +
+			"| アドレス |   12 |\n" +
+			"| a\\|b     |    3 |\n"},
+		{TableTSV, "項目\t件数\nアドレス\t12\na|b\t3\n"},
+		{TableASCII, "" +
+			"+----------+------+\n" +
+			"|   項目   | 件数 |\n" +
+			"+----------+------+\n" +
+			"| アドレス |   12 |\n" +
+			"| a|b      |    3 |\n" +
+			"+----------+------+\n"},
+	}
+	for _, tt := range tests {
+		var buf bytes.Buffer
+		writeTable(&buf, headers, rows,
+			TableOptions{Align: align, Format: tt.format}, 80)
+		if got := buf.String(); got != tt.want {
+			t.Errorf("writeTable %d: want\n%s\nbut\n%s", tt.format, tt.want, got)
+		}
+	}
+
+	// 端末の幅に収める
+	var buf bytes.Buffer
+	long := strings.Repeat("長い説明 ", 20)
+	writeTable(&buf, headers, [][]string{{long, "1"}},
+		TableOptions{Format: TableStyled}, 40)
+	for _, line := range strings.Split(strings.TrimSpace(buf.String()), "\n") {
+		if lipgloss.Width(line) > 40 {
+			t.Errorf("writeTable: want width <= 40, but %d: %s",
+				lipgloss.Width(line), line)
+		}
+	}
+
+	// 見出しがない場合
+	for _, format := range []TableFormat{TableStyled, TableASCII,
+		TableMarkdown, TableTSV} {
+		buf.Reset()
+		writeTable(&buf, nil, nil, TableOptions{Format: format}, 80)
+		if buf.Len() != 0 {
+			t.Errorf("writeTable %d: want no output, but %s", format, buf.String())
+		}
+		buf.Reset()
+		writeTable(&buf, nil, rows, TableOptions{Format: format}, 80)
+		if !strings.Contains(buf.String(), "12") {
+			t.Errorf("writeTable %d: want rows, but %s", format, buf.String())
+		}
+	}
+}
+
+func TestPrintTable(t *testing.T) {
+	oldOut := os.Stdout
+	r, w, _ := os.Pipe()
+	os.Stdout = w
+	PrintTable([]string{"項目", "設定値"}, [][]string{{"機器の種類", "FortiGate"}})
+	_ = w.Close()
+	os.Stdout = oldOut
+	out, _ := io.ReadAll(r)
+	got := string(out)
+	// 端末でない場合は Markdown
+	if !strings.Contains(got, "| 機器の種類 | FortiGate |") {
+		t.Errorf("want Markdown, but %s", got)
+	}
+}
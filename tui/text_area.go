@@ -0,0 +1,80 @@
+package tui
+
+import (
+	"github.com/charmbracelet/bubbles/textarea"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+type textAreaModel struct {
+	textarea textarea.Model
+	prompt   string
+	done     bool
+	quitting bool
+}
+
+func (m textAreaModel) Init() tea.Cmd {
+	return textarea.Blink
+}
+
+func (m textAreaModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.textarea.SetWidth(msg.Width - 4)
+		return m, nil
+
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "ctrl+c", "esc":
+			m.quitting = true
+			return m, tea.Quit
+
+		case "ctrl+d":
+			m.done = true
+			return m, tea.Quit
+		}
+	}
+
+	var cmd tea.Cmd
+	m.textarea, cmd = m.textarea.Update(msg)
+	return m, cmd
+}
+
+func (m textAreaModel) View() string {
+	if m.done || m.quitting {
+		return ""
+	}
+	return "\n" + titleStyle.Render(m.prompt) + "\n\n" +
+		itemStyle.Render(m.textarea.View()) + "\n" +
+		helpStyle.Render("ctrl+d: 決定・esc: 中止")
+}
+
+// newTextAreaModel creates the model of TextArea.
+func newTextAreaModel(prompt, value string) textAreaModel {
+	ta := textarea.New()
+	// ctrl+d は決定に使うため、後ろの文字の削除は delete のみとする
+	ta.KeyMap.DeleteCharacterForward.SetKeys("delete")
+	ta.SetValue(value)
+	ta.Focus()
+	return textAreaModel{textarea: ta, prompt: prompt}
+}
+
+// TextArea reads multiple lines of text, such as a comment of a change.
+// The default is shown as the initial value. Enter inserts a new line, and
+// Ctrl+D finishes the input as the end of input in a shell; therefore
+// Ctrl+D does not delete the character after the cursor, and only Delete
+// does. It returns ErrCanceled if canceled by Esc or Ctrl+C, and ErrNoTTY
+// if the standard input is not a terminal.
+//
+// Example:
+//
+//	memo, err := tui.TextArea("変更内容を入力してください", "")
+func TextArea(prompt, def string) (string, error) {
+	m, err := run(newTextAreaModel(prompt, def))
+	if err != nil {
+		return "", err
+	}
+	if !m.done {
+		return "", ErrCanceled
+	}
+	return m.textarea.Value(), nil
+}
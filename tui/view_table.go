@@ -0,0 +1,298 @@
+package tui
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/table"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// maxColumnWidth is the maximum display width of a column of ViewTable.
+// The longer cells are truncated with "…".
+const maxColumnWidth = 40
+
+// viewTableChrome is the number of the lines of ViewTable other than the
+// rows, such as the header, the search and the help.
+const viewTableChrome = 6
+
+var tableBorderStyle = lipgloss.NewStyle().
+	BorderStyle(lipgloss.NormalBorder()).
+	BorderForeground(lipgloss.Color("99"))
+
+type viewTableModel struct {
+	table     table.Model
+	headers   []string
+	rows      [][]string
+	order     []int // 表示する行 (rows の添字)
+	col       int   // 列のカーソル
+	sortCol   int   // 並べ替える列 (-1 は元の順)
+	sortDesc  bool
+	search    textinput.Model
+	searching bool
+	selected  int // 選択した行 (rows の添字)
+	done      bool
+	quitting  bool
+}
+
+// newViewTableModel creates the model of ViewTable.
+func newViewTableModel(headers []string, rows [][]string) viewTableModel {
+	// 列数を見出しに合わせる
+	normalized := make([][]string, len(rows))
+	for i, row := range rows {
+		normalized[i] = make([]string, len(headers))
+		copy(normalized[i], row)
+	}
+
+	s := table.DefaultStyles()
+	s.Header = s.Header.
+		BorderStyle(lipgloss.NormalBorder()).
+		BorderForeground(lipgloss.Color("99")).
+		BorderBottom(true).
+		Foreground(lipgloss.Color("99"))
+	s.Selected = s.Selected.Foreground(selectedItemStyle.GetForeground())
+
+	search := textinput.New()
+	search.Prompt = "/"
+
+	m := viewTableModel{
+		table: table.New(
+			table.WithFocused(true),
+			table.WithHeight(listHeight+1),
+			table.WithStyles(s),
+		),
+		headers:  headers,
+		rows:     normalized,
+		sortCol:  -1,
+		search:   search,
+		selected: -1,
+	}
+	m.table.SetColumns(m.columns())
+	m.refresh()
+	return m
+}
+
+// columnWidths returns the display widths of the columns, which fit the
+// headers with the column cursor and the sort mark, and the cells up to
+// maxColumnWidth.
+func columnWidths(headers []string, rows [][]string) []int {
+	widths := make([]int, len(headers))
+	for i, header := range headers {
+		// カーソルと並べ替えの印 (例: "▸ 名前 ▲")
+		widths[i] = lipgloss.Width(header) + 4
+	}
+	for _, row := range rows {
+		for i, cell := range row[:min(len(row), len(widths))] {
+			widths[i] = max(widths[i], lipgloss.Width(cell))
+		}
+	}
+	for i := range widths {
+		widths[i] = min(widths[i], maxColumnWidth)
+	}
+	return widths
+}
+
+// columns returns the columns of the table with the column cursor and the
+// sort mark.
+func (m viewTableModel) columns() []table.Column {
+	widths := columnWidths(m.headers, m.rows)
+	cols := make([]table.Column, len(m.headers))
+	for i, header := range m.headers {
+		if i == m.col {
+			header = "▸ " + header
+		}
+		if i == m.sortCol {
+			if m.sortDesc {
+				header += " ▼"
+			} else {
+				header += " ▲"
+			}
+		}
+		cols[i] = table.Column{Title: header, Width: widths[i]}
+	}
+	return cols
+}
+
+// refresh sorts and filters the rows, and sets them to the table.
+func (m *viewTableModel) refresh() {
+	query := strings.ToLower(m.search.Value())
+	m.order = nil
+	for i, row := range m.rows {
+		if query == "" || slices.ContainsFunc(row, func(cell string) bool {
+			return strings.Contains(strings.ToLower(cell), query)
+		}) {
+			m.order = append(m.order, i)
+		}
+	}
+	if m.sortCol != -1 {
+		slices.SortStableFunc(m.order, func(a, b int) int {
+			c := compareCells(m.rows[a][m.sortCol], m.rows[b][m.sortCol])
+			if m.sortDesc {
+				return -c
+			}
+			return c
+		})
+	}
+	rows := make([]table.Row, len(m.order))
+	for i, index := range m.order {
+		rows[i] = m.rows[index]
+	}
+	m.table.SetRows(rows)
+	m.table.SetColumns(m.columns())
+	m.table.GotoTop()
+}
+
+// compareCells compares the cells as numbers if both are numbers, and as
+// strings otherwise.
+func compareCells(a, b string) int {
+	x, errX := strconv.ParseFloat(strings.TrimSpace(a), 64)
+	y, errY := strconv.ParseFloat(strings.TrimSpace(b), 64)
+	if errX == nil && errY == nil {
+		return cmp.Compare(x, y)
+	}
+	return strings.Compare(a, b)
+}
+
+func (m viewTableModel) Init() tea.Cmd {
+	return nil
+}
+
+func (m viewTableModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.table.SetWidth(msg.Width)
+		m.table.SetHeight(max(msg.Height-viewTableChrome, 3))
+		return m, nil
+
+	case tea.KeyMsg:
+		if msg.String() == "ctrl+c" {
+			m.quitting = true
+			return m, tea.Quit
+		}
+		if m.searching {
+			// 検索文字列の入力中
+			switch msg.String() {
+			case "enter":
+				m.searching = false
+				m.search.Blur()
+				return m, nil
+			case "esc":
+				m.searching = false
+				m.search.Blur()
+				m.search.SetValue("")
+				m.refresh()
+				return m, nil
+			}
+			var cmd tea.Cmd
+			m.search, cmd = m.search.Update(msg)
+			m.refresh()
+			return m, cmd
+		}
+
+		switch keypress := msg.String(); keypress {
+		case "q":
+			m.quitting = true
+			return m, tea.Quit
+
+		case "esc":
+			if m.search.Value() != "" {
+				// 検索を解除する
+				m.search.SetValue("")
+				m.refresh()
+				return m, nil
+			}
+			m.quitting = true
+			return m, tea.Quit
+
+		case "/":
+			m.searching = true
+			return m, m.search.Focus()
+
+		case "enter":
+			if len(m.order) == 0 {
+				return m, nil
+			}
+			m.selected = m.order[m.table.Cursor()]
+			m.done = true
+			return m, tea.Quit
+
+		case "left", "h":
+			m.col = max(m.col-1, 0)
+			m.table.SetColumns(m.columns())
+			return m, nil
+
+		case "right", "l":
+			m.col = max(min(m.col+1, len(m.headers)-1), 0)
+			m.table.SetColumns(m.columns())
+			return m, nil
+
+		case "s":
+			if m.col < len(m.headers) {
+				m.sortBy(m.col)
+			}
+			return m, nil
+
+		case "1", "2", "3", "4", "5", "6", "7", "8", "9":
+			// 9 列目までは、数字で列を選んで並べ替える
+			if col := int(keypress[0] - '1'); col < len(m.headers) {
+				m.sortBy(col)
+			}
+			return m, nil
+		}
+	}
+
+	var cmd tea.Cmd
+	m.table, cmd = m.table.Update(msg)
+	return m, cmd
+}
+
+// sortBy moves the column cursor to col, and sorts the rows by the column.
+// The same column toggles the ascending and the descending order.
+func (m *viewTableModel) sortBy(col int) {
+	m.col = col
+	if col == m.sortCol {
+		m.sortDesc = !m.sortDesc
+	} else {
+		m.sortCol, m.sortDesc = col, false
+	}
+	m.refresh()
+}
+
+func (m viewTableModel) View() string {
+	if m.done || m.quitting {
+		return ""
+	}
+	s := tableBorderStyle.Render(m.table.View()) + "\n"
+	if m.searching || m.search.Value() != "" {
+		s += m.search.View() + " "
+	}
+	s += fmt.Sprintf("%d/%d 行", len(m.order), len(m.rows))
+	return s + "\n" + helpStyle.Render(
+		"↑/↓: 移動・←/→: 列・s/1-9: 列で並べ替え・/: 検索・enter: 選択・q: 終了")
+}
+
+// ViewTable shows the rows in a scrollable table, and returns the index of
+// the chosen row in rows. The widths of the columns fit the contents. Left
+// and right move the column cursor, and "s" sorts the rows by the column;
+// the number keys sort by one of the first nine columns directly. "/"
+// searches the rows containing the text. It returns ErrCanceled if closed by "q", Esc or
+// Ctrl+C, and ErrNoTTY if the standard input is not a terminal.
+//
+// Example:
+//
+//	i, err := tui.ViewTable([]string{"名前", "アドレス"}, addresses)
+func ViewTable(headers []string, rows [][]string) (int, error) {
+	m, err := run(newViewTableModel(headers, rows))
+	if err != nil {
+		return -1, err
+	}
+	if !m.done {
+		return -1, ErrCanceled
+	}
+	return m.selected, nil
+}
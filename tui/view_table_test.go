@@ -0,0 +1,89 @@
+package tui
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestViewTable(t *testing.T) {
+	headers := []string{"名前", "ポート"}
+	rows := [][]string{
+		{"http", "80"},
+		{"https", "443"},
+		{"ssh", "22"},
+		{"syslog", "514", "余分な列"},
+	}
+	if got := columnWidths(headers, rows); !slices.Equal(got, []int{8, 10}) {
+		t.Errorf("columnWidths: want [8 10], but %v", got)
+	}
+	long := strings.Repeat("あ", maxColumnWidth)
+	if got := columnWidths([]string{"A"}, [][]string{{long}}); got[0] != maxColumnWidth {
+		t.Errorf("columnWidths: want %d, but %v", maxColumnWidth, got)
+	}
+
+	m := newViewTableModel(headers, rows)
+	// ポートを数値として降順に並べ替える
+	m = press(m, keyMsg("2"), keyMsg("2")).(viewTableModel)
+	if !slices.Equal(m.order, []int{3, 1, 0, 2}) {
+		t.Errorf("sort: want [3 1 0 2], but %v", m.order)
+	}
+	if !strings.Contains(m.View(), "ポート ▼") {
+		t.Errorf("View: want sort mark, but %s", m.View())
+	}
+	m = press(m, keyDown, keyEnter).(viewTableModel)
+	if !m.done || m.selected != 1 {
+		t.Errorf("ViewTable: want row 1, but %d (done %v)", m.selected, m.done)
+	}
+
+	// 10 列目以降も、列のカーソルで並べ替える
+	var many []string
+	for i := range 11 {
+		many = append(many, fmt.Sprintf("列%d", i+1))
+	}
+	keyRight := tea.KeyMsg{Type: tea.KeyRight}
+	keyLeft := tea.KeyMsg{Type: tea.KeyLeft}
+	keyS := keyMsg("s")
+	m = newViewTableModel(many, [][]string{
+		{10: "b"}, {10: "c"}, {10: "a"}})
+	for range 12 {
+		m = press(m, keyRight).(viewTableModel)
+	}
+	m = press(m, keyLeft, keyRight, keyS).(viewTableModel)
+	if m.col != 10 || m.sortCol != 10 || !slices.Equal(m.order, []int{2, 0, 1}) {
+		t.Errorf("sort: want column 10 [2 0 1], but %d %d %v",
+			m.col, m.sortCol, m.order)
+	}
+	if !strings.Contains(m.View(), "▸ 列11 ▲") {
+		t.Errorf("View: want cursor and sort mark, but %s", m.View())
+	}
+	m = press(m, keyS).(viewTableModel)
+	if !m.sortDesc {
+		t.Errorf("sort: want descending")
+	}
+
+	// 検索
+	m = newViewTableModel(headers, rows)
+	m = press(m, keyMsg("/"), keyMsg("S"), keyEnter).(viewTableModel)
+	if !slices.Equal(m.order, []int{1, 2, 3}) || m.searching {
+		t.Errorf("search: want [1 2 3], but %v", m.order)
+	}
+	m = press(m, keyEsc).(viewTableModel)
+	if len(m.order) != len(rows) || m.quitting {
+		t.Errorf("search: want cleared, but %v", m.order)
+	}
+	m = press(m, keyEsc).(viewTableModel)
+	if !m.quitting {
+		t.Errorf("ViewTable: want closed by Esc")
+	}
+
+	// 一致する行がない場合は選択できない
+	m = press(newViewTableModel(headers, rows), keyMsg("/"), keyMsg("none"),
+		keyEnter, keyEnter).(viewTableModel)
+	if m.done {
+		t.Errorf("ViewTable: want not done without rows")
+	}
+}